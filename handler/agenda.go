@@ -0,0 +1,489 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const agendaDoneOption = "Done"
+
+// parseAgendaSession reads "Title | 09:00-10:30 | Room | Speaker | Capacity", where room, speaker and capacity are optional
+func parseAgendaSession(text string) (*model.AgendaSession, string) {
+	invalid := "Please describe the session like this: Title | 09:00-10:30 | Room | Speaker | Capacity\n" +
+		"Room, speaker and capacity are optional, e.g. Opening keynote | 09:00-09:45 | Main hall"
+
+	fields := strings.Split(text, "|")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	if len(fields) < 2 || len(fields) > 5 || fields[0] == "" {
+		return nil, invalid
+	}
+
+	startText, endText, ok := strings.Cut(fields[1], "-")
+	start, startErr := time.Parse("15:04", strings.TrimSpace(startText))
+	end, endErr := time.Parse("15:04", strings.TrimSpace(endText))
+	if !ok || startErr != nil || endErr != nil {
+		return nil, fmt.Sprintf("'%s' is not a valid time range. Use the 24-hour format, e.g. 09:00-10:30.", fields[1])
+	}
+	if !end.After(start) {
+		return nil, "A session has to end after it starts."
+	}
+
+	session := &model.AgendaSession{
+		Title: fields[0],
+		Start: start.Format("15:04"),
+		End:   end.Format("15:04"),
+	}
+	if len(fields) > 2 {
+		session.Room = fields[2]
+	}
+	if len(fields) > 3 {
+		session.Speaker = fields[3]
+	}
+	if len(fields) > 4 && fields[4] != "" {
+		capacity, err := strconv.Atoi(fields[4])
+		if err != nil || capacity < 1 {
+			return nil, fmt.Sprintf("'%s' is not a valid capacity. Use a positive number, or leave it out for no limit.", fields[4])
+		}
+		session.Capacity = capacity
+	}
+	return session, ""
+}
+
+// describeAgendaSession renders a session on one line with how many places are taken
+func describeAgendaSession(number int, session *model.AgendaSession) string {
+	text := fmt.Sprintf("%d. %s-%s %s", number, session.Start, session.End, session.Title)
+
+	var where []string
+	if session.Room != "" {
+		where = append(where, session.Room)
+	}
+	if session.Speaker != "" {
+		where = append(where, session.Speaker)
+	}
+	if len(where) > 0 {
+		text += " (" + strings.Join(where, ", ") + ")"
+	}
+
+	if session.Capacity > 0 {
+		text += fmt.Sprintf(" – %d/%d places taken", len(session.Attendees), session.Capacity)
+	}
+	return text
+}
+
+// parseSessionNumber reads a 1-based agenda position, returning the session or an error message
+func parseSessionNumber(event *model.Event, text string) (*model.AgendaSession, string) {
+	number, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || number < 1 || number > len(event.Agenda) {
+		if len(event.Agenda) == 0 {
+			return nil, fmt.Sprintf("'%s' has no agenda yet.", event.Name)
+		}
+		return nil, fmt.Sprintf("Please give a session number between 1 and %d. See them with /agenda %s.", len(event.Agenda), event.ID)
+	}
+	return &event.Agenda[number-1], ""
+}
+
+// readEventForAgenda checks the organiser's permission and reads the event whose agenda they want to use
+func (o *OrganiserBotHandler) readEventForAgenda(ctx context.Context, eventID string, organiserID int64, permission model.Permission, action string) (*model.Event, string) {
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, organiserID, permission)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return nil, fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return nil, fmt.Sprintf("You don't have permission to %s for this event.", action)
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return nil, fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+	return event, ""
+}
+
+func (o *OrganiserBotHandler) handleAddingAgendaSession(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, description, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	session, errText := parseAgendaSession(description)
+	if errText != "" {
+		return errText
+	}
+
+	_, errText = o.readEventForAgenda(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent, "change the agenda")
+	if errText != "" {
+		return errText
+	}
+
+	event, err := o.FirebaseConnector.AddAgendaSession(ctx, eventID, *session)
+	if err != nil {
+		log.Println("error adding agenda session:", err)
+		return "Error adding the session. Please try again."
+	}
+	return fmt.Sprintf("Added '%s' to the agenda of '%s'.\n\n%s", session.Title, event.Name, agendaText(event))
+}
+
+// agendaText lists the event's sessions for organisers with sign-up and check-in counts
+func agendaText(event *model.Event) string {
+	if len(event.Agenda) == 0 {
+		return fmt.Sprintf("'%s' has no agenda yet. Add sessions with /addSession %s.", event.Name, event.ID)
+	}
+
+	text := fmt.Sprintf("Agenda for '%s' on %s:\n", event.Name, event.EventDate.Format("2006-01-02"))
+	for i := range event.Agenda {
+		session := &event.Agenda[i]
+		text += describeAgendaSession(i+1, session)
+		if session.Capacity == 0 {
+			text += fmt.Sprintf(" – %d signed up", len(session.Attendees))
+		}
+		text += fmt.Sprintf(", %d checked in\n", len(session.CheckedIn))
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleViewingAgenda(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	event, errText := o.readEventForAgenda(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants, "view the agenda")
+	if errText != "" {
+		return errText
+	}
+	return agendaText(event)
+}
+
+func (o *OrganiserBotHandler) handleRemovingAgendaSession(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return "Invalid format. Please use: EVENT_REF_CODE SESSION_NUMBER\nExample: ABC123 2"
+	}
+
+	event, errText := o.readEventForAgenda(ctx, parts[0], update.Message.From.ID, model.PermissionEditEvent, "change the agenda")
+	if errText != "" {
+		return errText
+	}
+	session, errText := parseSessionNumber(event, parts[1])
+	if errText != "" {
+		return errText
+	}
+
+	removed, err := o.FirebaseConnector.RemoveAgendaSession(ctx, event.ID, session.ID)
+	if errors.Is(err, model.ErrSessionDoesNotExist) {
+		return "That session has already been removed."
+	} else if err != nil {
+		log.Println("error removing agenda session:", err)
+		return "Error removing the session. Please try again."
+	}
+
+	text := fmt.Sprintf("Removed '%s' from the agenda.", removed.Title)
+	if len(removed.Attendees) > 0 {
+		text += fmt.Sprintf(" %d participants had signed up for it; you may want to tell them with /blast %s.", len(removed.Attendees), event.ID)
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleViewingSessionRoster(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return "Invalid format. Please use: EVENT_REF_CODE SESSION_NUMBER\nExample: ABC123 2"
+	}
+
+	event, errText := o.readEventForAgenda(ctx, parts[0], update.Message.From.ID, model.PermissionViewParticipants, "view session rosters")
+	if errText != "" {
+		return errText
+	}
+	session, errText := parseSessionNumber(event, parts[1])
+	if errText != "" {
+		return errText
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", event.ID, err)
+		return "Error retrieving participants. Please try again."
+	}
+
+	number, _ := strconv.Atoi(parts[1])
+	text := fmt.Sprintf("Roster for %s\n%d signed up, %d checked in\n\n",
+		describeAgendaSession(number, session), len(session.Attendees), len(session.CheckedIn))
+	if len(session.Attendees) == 0 {
+		return text + "No one has signed up for this session yet."
+	}
+
+	for _, participant := range participants {
+		if !slices.Contains(session.Attendees, participant.ID) {
+			continue
+		}
+		mark := "⬜"
+		if slices.Contains(session.CheckedIn, participant.ID) {
+			mark = "✅"
+		}
+		text += fmt.Sprintf("%s %s", mark, participant.Name)
+		if participant.Username != "" {
+			text += " (@" + participant.Username + ")"
+		}
+		text += fmt.Sprintf(" [ID: %d]\n", participant.UserID)
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleSessionCheckIn(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.applySessionCheckIn(ctx, update, userState, false)
+}
+
+func (o *OrganiserBotHandler) handleUndoSessionCheckIn(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.applySessionCheckIn(ctx, update, userState, true)
+}
+
+// applySessionCheckIn checks the participant named in "EVENT_REF_CODE SESSION_NUMBER NAME_OR_USER_ID" in at the session, or undoes it
+func (o *OrganiserBotHandler) applySessionCheckIn(ctx context.Context, update *models.Update, userState *model.UserState, undo bool) string {
+	userState.State = model.StateIdle
+
+	parts := strings.SplitN(strings.TrimSpace(update.Message.Text), " ", 3)
+	if len(parts) != 3 || strings.TrimSpace(parts[2]) == "" {
+		return "Invalid format. Please use: EVENT_REF_CODE SESSION_NUMBER NAME_OR_USER_ID\nExample: ABC123 2 Alice"
+	}
+	query := strings.TrimSpace(parts[2])
+
+	event, errText := o.readEventForAgenda(ctx, parts[0], update.Message.From.ID, model.PermissionCheckIn, "check participants in")
+	if errText != "" {
+		return errText
+	}
+	if blockedText := checkInBlockedText(event); blockedText != "" && !undo {
+		return blockedText
+	}
+	session, errText := parseSessionNumber(event, parts[1])
+	if errText != "" {
+		return errText
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", event.ID, err)
+		return "Error retrieving participants. Please try again."
+	}
+
+	matches := matchParticipants(participants, query)
+	switch {
+	case len(matches) == 0:
+		return fmt.Sprintf("No participant of '%s' matches '%s'.", event.Name, query)
+	case len(matches) > 1:
+		text := fmt.Sprintf("Several participants match '%s'. Please send the command again with one of their User IDs:\n", query)
+		for i, participant := range matches {
+			if i == maxManualCheckInMatches {
+				break
+			}
+			text += fmt.Sprintf("- %s [ID: %d]\n", participant.Name, participant.UserID)
+		}
+		return text
+	}
+	participant := &matches[0]
+
+	_, err = o.FirebaseConnector.SetAgendaCheckIn(ctx, event.ID, session.ID, participant.ID, !undo)
+	switch {
+	case errors.Is(err, model.ErrAlreadyCheckedIn):
+		return fmt.Sprintf("%s is already checked in at '%s'.", participant.Name, session.Title)
+	case errors.Is(err, model.ErrNotCheckedIn):
+		return fmt.Sprintf("%s is not checked in at '%s'.", participant.Name, session.Title)
+	case errors.Is(err, model.ErrNotSignedUp):
+		return fmt.Sprintf("%s is no longer a participant of '%s'.", participant.Name, event.Name)
+	case errors.Is(err, model.ErrSessionDoesNotExist):
+		return "That session has been removed from the agenda."
+	case err != nil:
+		log.Println("error updating session check-in:", err)
+		return "Error updating the check-in. Please try again."
+	}
+
+	if undo {
+		return fmt.Sprintf("%s is no longer checked in at '%s'.", participant.Name, session.Title)
+	}
+	text := fmt.Sprintf("✅ %s checked in at '%s'.", participant.Name, session.Title)
+	if !slices.Contains(session.Attendees, participant.ID) {
+		text += " They hadn't signed up for it, so they were added as a walk-in."
+	}
+	return text
+}
+
+// participantAgendaText lists the event's sessions for a participant, marking the ones they picked
+func participantAgendaText(event *model.Event, participantID string) string {
+	text := fmt.Sprintf("Agenda for '%s' on %s:\n", event.Name, event.EventDate.Format("2006-01-02"))
+	for i := range event.Agenda {
+		session := &event.Agenda[i]
+		switch {
+		case slices.Contains(session.Attendees, participantID):
+			text += "✅ "
+		case session.IsFull():
+			text += "⛔ "
+		default:
+			text += "⬜ "
+		}
+		text += describeAgendaSession(i+1, session) + "\n"
+	}
+	return text + "\nSend a session number to sign up for it or drop it, or 'Done' when you're finished."
+}
+
+func (p *ParticipantBotHandler) handleSelectAgendaEvent(ctx context.Context, userState *model.UserState) {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(p.update.Message.Text)
+
+	text := ""
+	event, err := p.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		text = fmt.Sprintf("Error finding event with ID '%s'. Please check the ID and try again.", eventID)
+	} else {
+		event.ID = eventID
+		text = p.openAgenda(ctx, userState, event)
+	}
+
+	markup := models.ReplyMarkup(getParticipantMainMenuKeyboard())
+	if userState.State == model.StateBrowsingAgenda {
+		markup = agendaKeyboard()
+	}
+	_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// openAgenda shows a participant the event's agenda to pick sessions from, or explains why they can't
+func (p *ParticipantBotHandler) openAgenda(ctx context.Context, userState *model.UserState, event *model.Event) string {
+	if len(event.Agenda) == 0 {
+		return fmt.Sprintf("'%s' doesn't have an agenda.", event.Name)
+	}
+	if event.IsDeleted() || event.CurrentStatus() != model.EventStatusPublished {
+		return fmt.Sprintf("Sessions of '%s' can't be picked any more.", event.Name)
+	}
+
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		return "You are not registered for this event. Please join the event first."
+	}
+	signedUpEvent := findSignedUpEvent(participant, event.ID)
+	if signedUpEvent == nil {
+		return "You are not registered for this event. Please join the event first."
+	}
+	if signedUpEvent.PendingApproval {
+		return "Your application to join this event is still awaiting the organiser's approval. You can pick sessions once it's approved."
+	}
+
+	userState.CurrentEvent = event
+	userState.TempOptions = []string{participant.ID}
+	userState.State = model.StateBrowsingAgenda
+	return participantAgendaText(event, participant.ID)
+}
+
+func (p *ParticipantBotHandler) handleBrowsingAgenda(ctx context.Context, userState *model.UserState) {
+	event := userState.CurrentEvent
+	participantID := userState.TempOptions[0]
+
+	var text string
+	if strings.EqualFold(strings.TrimSpace(p.update.Message.Text), agendaDoneOption) || p.update.Message.Text == "Cancel" {
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		userState.TempOptions = nil
+		text = "Your sessions are saved. Use /agenda any time to change them."
+	} else if session, errText := parseSessionNumber(event, p.update.Message.Text); errText != "" {
+		text = errText
+	} else {
+		text = p.toggleAgendaSession(ctx, userState, session, participantID)
+	}
+
+	markup := models.ReplyMarkup(agendaKeyboard())
+	if userState.State != model.StateBrowsingAgenda {
+		markup = getParticipantMainMenuKeyboard()
+	}
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// toggleAgendaSession signs the participant up for the session or drops it, and shows the agenda again
+func (p *ParticipantBotHandler) toggleAgendaSession(ctx context.Context, userState *model.UserState, session *model.AgendaSession, participantID string) string {
+	event := userState.CurrentEvent
+	leaving := slices.Contains(session.Attendees, participantID)
+
+	var updated *model.Event
+	var err error
+	if leaving {
+		updated, err = p.FirebaseConnector.LeaveAgendaSession(ctx, event.ID, session.ID, participantID)
+	} else {
+		updated, err = p.FirebaseConnector.JoinAgendaSession(ctx, event.ID, session.ID, participantID)
+	}
+
+	var result string
+	switch {
+	case errors.Is(err, model.ErrSessionFull):
+		result = fmt.Sprintf("Sorry, '%s' is full.", session.Title)
+	case errors.Is(err, model.ErrSessionClash):
+		result = fmt.Sprintf("'%s' overlaps another session you picked", session.Title)
+		if clash := event.ClashingSession(session, participantID); clash != nil {
+			result += fmt.Sprintf(" ('%s')", clash.Title)
+		}
+		result += ". Drop that one first."
+	case errors.Is(err, model.ErrSessionDoesNotExist):
+		result = fmt.Sprintf("'%s' has been removed from the agenda.", session.Title)
+	case errors.Is(err, model.ErrNotSignedUp):
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		userState.TempOptions = nil
+		return fmt.Sprintf("You are no longer registered for '%s'.", event.Name)
+	case err != nil:
+		log.Println("error updating agenda session:", err)
+		return "Error updating your sessions. Please try again."
+	case leaving:
+		result = fmt.Sprintf("You have dropped '%s'.", session.Title)
+	default:
+		result = fmt.Sprintf("You're signed up for '%s'.", session.Title)
+	}
+
+	if updated == nil {
+		// Show the latest places even when the change was refused
+		updated, err = p.FirebaseConnector.ReadEvent(ctx, event.ID)
+		if err != nil {
+			log.Println("error reading event:", err)
+			return result
+		}
+		updated.ID = event.ID
+	}
+	userState.CurrentEvent = updated
+	return result + "\n\n" + participantAgendaText(updated, participantID)
+}
+
+func agendaKeyboard() *models.ReplyKeyboardMarkup {
+	return &models.ReplyKeyboardMarkup{
+		Keyboard: [][]models.KeyboardButton{
+			{
+				{Text: agendaDoneOption},
+			},
+		},
+		ResizeKeyboard: true,
+	}
+}
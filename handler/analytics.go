@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Width of the text bar charts in RSVP summaries
+	summaryBarWidth = 10
+
+	// Numeric questions with more distinct values than this only show the average
+	maxNumberBuckets = 10
+)
+
+func (o *OrganiserBotHandler) handleRSVPSummary(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view the RSVP summary for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	if len(event.RSVPQuestions) == 0 {
+		return fmt.Sprintf("Event '%s' has no RSVP questions.", event.Name)
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+
+	return buildRSVPSummary(event, participants)
+}
+
+// buildRSVPSummary aggregates the participants' RSVP answers into a text report
+func buildRSVPSummary(event *model.Event, participants []model.Participant) string {
+	// Gather every sign-up for this event once
+	var signUps []*model.SignedUpEvent
+	for i := range participants {
+		if signedUpEvent := findSignedUpEvent(&participants[i], event.ID); signedUpEvent != nil {
+			signUps = append(signUps, signedUpEvent)
+		}
+	}
+	total := len(signUps)
+
+	completed := 0
+	for _, signedUpEvent := range signUps {
+		answeredAll := true
+		for _, question := range event.RSVPQuestions {
+			if len(findRSVPAnswers(signedUpEvent, question.ID)) == 0 {
+				answeredAll = false
+				break
+			}
+		}
+		if answeredAll {
+			completed++
+		}
+	}
+
+	text := fmt.Sprintf("RSVP summary for '%s'\n", event.Name)
+	text += fmt.Sprintf("Participants: %d\n", total)
+	text += fmt.Sprintf("Completed all questions: %d (%s)\n", completed, formatPercent(completed, total))
+
+	for i, question := range event.RSVPQuestions {
+		var responses [][]string
+		for _, signedUpEvent := range signUps {
+			if answers := findRSVPAnswers(signedUpEvent, question.ID); len(answers) > 0 {
+				responses = append(responses, answers)
+			}
+		}
+
+		text += fmt.Sprintf("\n%d. %s (%s)\n", i+1, question.Question, getRSVPTypeString(question.Type))
+		text += fmt.Sprintf("Responses: %d/%d (%s)\n", len(responses), total, formatPercent(len(responses), total))
+
+		switch question.Type {
+		case model.QuestionTypeYesNo, model.QuestionTypeMCQ, model.QuestionTypeMultiSelect:
+			text += summariseOptions(question, responses)
+		case model.QuestionTypeRating, model.QuestionTypeNumber:
+			text += summariseNumbers(question, responses)
+		case model.QuestionTypeShortAnswer:
+			text += fmt.Sprintf("%d short answers received. Use /exportParticipants to read them.\n", len(responses))
+		}
+	}
+
+	return text
+}
+
+// summariseOptions counts how often each option was picked; percentages are of respondents
+func summariseOptions(question model.RSVPQuestion, responses [][]string) string {
+	counts := make(map[string]int)
+	other := 0
+	for _, answers := range responses {
+		hasOther := false
+		for _, answer := range answers {
+			if slices.Contains(question.Options, answer) {
+				counts[answer]++
+			} else {
+				hasOther = true
+			}
+		}
+		if hasOther {
+			other++
+		}
+	}
+
+	var text string
+	for _, option := range question.Options {
+		text += formatBarLine(option, counts[option], len(responses))
+	}
+	if other > 0 {
+		text += formatBarLine("Other", other, len(responses))
+	}
+	if question.Type == model.QuestionTypeMultiSelect && len(responses) > 0 {
+		text += "(Respondents could pick more than one option)\n"
+	}
+	return text
+}
+
+// summariseNumbers reports the average, range and distribution of numeric answers
+func summariseNumbers(question model.RSVPQuestion, responses [][]string) string {
+	var values []float64
+	for _, answers := range responses {
+		value, err := strconv.ParseFloat(strings.TrimSpace(answers[0]), 64)
+		if err != nil {
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	sort.Float64s(values)
+	text := fmt.Sprintf("Average: %s (min %s, max %s)\n",
+		formatNumber(sum/float64(len(values))), formatNumber(values[0]), formatNumber(values[len(values)-1]))
+
+	// Ratings always show the full scale; other numbers show each distinct value given
+	buckets := question.Options
+	if question.Type != model.QuestionTypeRating {
+		buckets = nil
+		for _, value := range values {
+			if label := formatNumber(value); !slices.Contains(buckets, label) {
+				buckets = append(buckets, label)
+			}
+		}
+	}
+
+	if len(buckets) > maxNumberBuckets {
+		return text + fmt.Sprintf("%d different values given. Use /exportParticipants to see them all.\n", len(buckets))
+	}
+
+	counts := make(map[string]int)
+	for _, value := range values {
+		counts[formatNumber(value)]++
+	}
+	for _, bucket := range buckets {
+		text += formatBarLine(bucket, counts[bucket], len(values))
+	}
+	return text
+}
+
+// formatBarLine renders one row of a text bar chart, e.g. "██████░░░░ 60% (6) Chicken"
+func formatBarLine(label string, count int, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = int(math.Round(float64(count) / float64(total) * summaryBarWidth))
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", summaryBarWidth-filled)
+	return fmt.Sprintf("%s %s (%d) %s\n", bar, formatPercent(count, total), count, label)
+}
+
+func formatPercent(count int, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(count)/float64(total)*100)
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
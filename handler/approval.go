@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func (o *OrganiserBotHandler) handleSettingApproval(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, requiresApproval, errText := parseEventToggle(update.Message.Text)
+	if errText != "" {
+		return errText
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to change how people join this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	event.RequiresApproval = requiresApproval
+	err = o.FirebaseConnector.UpdateEvent(ctx, eventID, *event)
+	if err != nil {
+		log.Println("error updating event:", err)
+		return "Error updating the event. Please try again."
+	}
+
+	if requiresApproval {
+		return fmt.Sprintf("People joining '%s' will now apply and wait for approval. Review applications with /applications %s.", event.Name, eventID)
+	}
+	text := fmt.Sprintf("Anyone with the event code can now join '%s' straight away.", event.Name)
+	if len(event.Applicants) > 0 {
+		text += fmt.Sprintf("\n%d application(s) are still waiting. Review them with /applications %s.", len(event.Applicants), eventID)
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleListApplications(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view applications for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	applicants, err := o.FirebaseConnector.ListApplicants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading applicants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving applications. Please try again."
+	}
+	if len(applicants) == 0 {
+		return fmt.Sprintf("No applications are waiting for '%s'.", event.Name)
+	}
+
+	text := fmt.Sprintf("Applications waiting for '%s':\n", event.Name)
+	for i := range applicants {
+		text += fmt.Sprintf("\n%d. %s", i+1, describeApplication(event, &applicants[i]))
+	}
+	text += fmt.Sprintf("\nUse /approve %s <User_ID> or /reject %s <User_ID> [Reason].", eventID, eventID)
+	return text
+}
+
+func (o *OrganiserBotHandler) handleApproveApplication(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.decideApplication(ctx, update, userState, true)
+}
+
+func (o *OrganiserBotHandler) handleRejectApplication(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.decideApplication(ctx, update, userState, false)
+}
+
+// decideApplication approves or rejects the application named in "EVENT_REF_CODE USER_ID [REASON]" and tells the applicant
+func (o *OrganiserBotHandler) decideApplication(ctx context.Context, update *models.Update, userState *model.UserState, approve bool) string {
+	userState.State = model.StateIdle
+
+	input, reason := strings.TrimSpace(update.Message.Text), ""
+	if parts := strings.SplitN(input, " ", 3); !approve && len(parts) == 3 {
+		input, reason = parts[0]+" "+parts[1], strings.TrimSpace(parts[2])
+	}
+	eventID, userID, errText := parseEventAndUserID(input)
+	if errText != "" {
+		return errText
+	}
+
+	organiserID := update.Message.From.ID
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, organiserID, model.PermissionManageParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to review applications for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	participant, err := o.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+	if err != nil {
+		log.Println("error reading participant:", err)
+		return "Error retrieving the applicant. Please try again."
+	}
+	if participant == nil {
+		return fmt.Sprintf("User %d has no application waiting for '%s'.", userID, event.Name)
+	}
+
+	applicantID, applicantName := participant.ID, participant.Name
+	action := model.AuditActionRejected
+	if approve {
+		action = model.AuditActionApproved
+		participant, err = o.FirebaseConnector.ApproveApplication(ctx, eventID, participant.ID)
+	} else {
+		participant, err = o.FirebaseConnector.RejectApplication(ctx, eventID, participant.ID)
+	}
+	if errors.Is(err, model.ErrNoApplication) {
+		return fmt.Sprintf("User %d has no application waiting for '%s'. It may already have been reviewed.", userID, event.Name)
+	} else if errors.Is(err, model.ErrEventFull) {
+		return fmt.Sprintf("'%s' is full: %d of %d places are taken, and approving %s would add %d more. Raise the limit with /setCapacity or reject the application.",
+			event.Name, event.Headcount(), event.Capacity, applicantName, 1+event.GuestCounts[applicantID])
+	} else if err != nil {
+		log.Println("error deciding application:", err)
+		return "Error updating the application. Please try again."
+	}
+
+	err = o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      eventID,
+		Action:       action,
+		ActorID:      organiserID,
+		TargetUserID: userID,
+		Details:      reason,
+		At:           time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+
+	var text, applicantText string
+	if approve {
+		notifyCheckIn(eventID)
+		text = fmt.Sprintf("%s has been approved for '%s'.", participant.Name, event.Name)
+		applicantText = fmt.Sprintf("🎉 Your application to join '%s' has been approved! Use /myTicket to get your QR ticket.", event.Name)
+	} else {
+		text = fmt.Sprintf("%s's application to '%s' has been rejected.", participant.Name, event.Name)
+		applicantText = fmt.Sprintf("Sorry, your application to join '%s' was not approved.", event.Name)
+		if reason != "" {
+			applicantText += "\nReason: " + reason
+		}
+	}
+
+	participantBot, err := newParticipantBot()
+	if err == nil {
+		_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: userID,
+			Text:   applicantText,
+		})
+	}
+	if err != nil {
+		log.Printf("error notifying applicant %d: %v", userID, err)
+		return text + "\nThey could not be notified."
+	}
+	return text + " They have been notified."
+}
+
+// describeApplication lists an applicant's details and their answers to the event's RSVP questions
+func describeApplication(event *model.Event, participant *model.Participant) string {
+	text := fmt.Sprintf("%s [ID: %d]", participant.Name, participant.UserID)
+	if participant.Username != "" {
+		text += " @" + participant.Username
+	}
+	text += "\n"
+
+	signedUpEvent := findSignedUpEvent(participant, event.ID)
+	if signedUpEvent == nil {
+		return text
+	}
+	if invite := formatInviteUsed(signedUpEvent); invite != "" {
+		text += fmt.Sprintf("   Invite: %s\n", invite)
+	}
+	for _, question := range event.RSVPQuestions {
+		answers := findRSVPAnswers(signedUpEvent, question.ID)
+		answer := "(not answered yet)"
+		if len(answers) > 0 {
+			answer = strings.Join(answers, ", ")
+		}
+		text += fmt.Sprintf("   Q: %s\n   A: %s\n", question.Question, answer)
+	}
+	return text
+}
+
+// notifyOrganisersOfApplication sends the event's owner and coowners an applicant's details to review
+func (p *ParticipantBotHandler) notifyOrganisersOfApplication(ctx context.Context, event *model.Event, participant *model.Participant) {
+	organiserBot, err := newOrganiserBot()
+	if err != nil {
+		log.Println("error creating organiser bot:", err)
+		return
+	}
+
+	text := fmt.Sprintf("📝 New application for '%s':\n%s\nApprove: /approve %s %d\nReject: /reject %s %d [Reason]",
+		event.Name, describeApplication(event, participant), event.ID, participant.UserID, event.ID, participant.UserID)
+	for _, organiserID := range event.MembersWith(model.PermissionManageParticipants) {
+		_, err = organiserBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: organiserID,
+			Text:   text,
+		})
+		if err != nil {
+			log.Printf("error notifying organiser %d: %v", organiserID, err)
+		}
+	}
+}
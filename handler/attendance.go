@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"EventBot/model"
+	"EventBot/repo"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// A participant is a chronic no-show once they have missed at least this many of an organiser's events...
+	chronicNoShowMinimum = 3
+	// ...and at least half of the ones they signed up for
+	chronicNoShowRate = 0.5
+
+	// No-show names listed in the text report before it points to the CSV
+	attendanceReportMaxNames = 30
+)
+
+// Attendance status of a participant for a single event
+const (
+	attendanceAttended = "Attended"
+	attendanceWalkIn   = "Walk-in"
+	attendanceNoShow   = "No-show"
+	attendancePending  = "Not checked in"
+)
+
+// attendanceHistory counts a participant's registrations and attendance across an organiser's finished events
+type attendanceHistory struct {
+	Registered int
+	Attended   int
+}
+
+func (h attendanceHistory) NoShows() int {
+	return h.Registered - h.Attended
+}
+
+func (h attendanceHistory) IsChronicNoShow() bool {
+	return h.NoShows() >= chronicNoShowMinimum && float64(h.NoShows()) >= chronicNoShowRate*float64(h.Registered)
+}
+
+// finishedEventIDs returns the IDs of an organiser's events whose check-in has closed, leaving out excludeEventID
+func finishedEventIDs(ctx context.Context, fc repo.FirestoreConnector, organiserID int64, excludeEventID string) (map[string]bool, error) {
+	events, err := fc.ListEventsByUserID(ctx, organiserID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := locationForOrganiser(ctx, fc, organiserID)
+	now := time.Now()
+	finished := make(map[string]bool)
+	for i := range events {
+		// No one could attend cancelled or deleted events, so they don't count towards no-shows
+		if events[i].CurrentStatus() == model.EventStatusCancelled || events[i].IsDeleted() {
+			continue
+		}
+		if events[i].ID != excludeEventID && !checkInClosesAt(&events[i], loc).After(now) {
+			finished[events[i].ID] = true
+		}
+	}
+	return finished, nil
+}
+
+// attendanceHistoryFor tallies the participant's sign-ups among the given finished events
+func attendanceHistoryFor(participant *model.Participant, finishedEvents map[string]bool) attendanceHistory {
+	var history attendanceHistory
+	for _, signedUpEvent := range participant.SignedUpEvents {
+		if !finishedEvents[signedUpEvent.EventID] || signedUpEvent.PendingApproval {
+			continue
+		}
+		history.Registered++
+		if signedUpEvent.CheckedIn {
+			history.Attended++
+		}
+	}
+	return history
+}
+
+// attendanceStatus classifies a participant for an event; walk-ins signed up once check-in had opened
+func attendanceStatus(signedUpEvent *model.SignedUpEvent, opensAt time.Time, finished bool) string {
+	switch {
+	case signedUpEvent.CheckedIn && !signedUpEvent.JoinedAt.IsZero() && !signedUpEvent.JoinedAt.Before(opensAt):
+		return attendanceWalkIn
+	case signedUpEvent.CheckedIn:
+		return attendanceAttended
+	case finished:
+		return attendanceNoShow
+	default:
+		return attendancePending
+	}
+}
+
+func (o *OrganiserBotHandler) handleAttendanceReport(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view the attendance report for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+	if len(participants) == 0 {
+		return fmt.Sprintf("No participants found for event '%s'.", event.Name)
+	}
+
+	// History is measured across the primary owner's other finished events
+	finishedEvents, err := finishedEventIDs(ctx, o.FirebaseConnector, event.UserID, event.ID)
+	if err != nil {
+		log.Println("error listing organiser events:", err)
+		return "Error retrieving past events. Please try again."
+	}
+
+	ownerLoc := locationForOrganiser(ctx, o.FirebaseConnector, event.UserID)
+	windows := effectiveCheckInWindows(event, ownerLoc)
+	opensAt := windows[0].OpensAt
+	for _, window := range windows {
+		if window.OpensAt.Before(opensAt) {
+			opensAt = window.OpensAt
+		}
+	}
+	finished := !checkInClosesAt(event, ownerLoc).After(time.Now())
+
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].Name < participants[j].Name
+	})
+
+	counts := make(map[string]int)
+	var noShows, chronic []string
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	err = w.Write([]string{"Name", "Telegram User ID", "Username", "Status", "Joined At", "Checked In At",
+		"Past Events Registered", "Past Events Attended", "Past No-shows", "Chronic No-show"})
+	if err != nil {
+		log.Println("error building attendance CSV:", err)
+		return "Error generating the attendance report. Please try again."
+	}
+
+	for i := range participants {
+		participant := &participants[i]
+		signedUpEvent := findSignedUpEvent(participant, event.ID)
+		if signedUpEvent == nil {
+			continue
+		}
+
+		status := attendanceStatus(signedUpEvent, opensAt, finished)
+		counts[status]++
+		if status == attendanceNoShow {
+			noShows = append(noShows, participant.Name)
+		}
+
+		history := attendanceHistoryFor(participant, finishedEvents)
+		if history.IsChronicNoShow() {
+			chronic = append(chronic, fmt.Sprintf("%s (missed %d of %d)", participant.Name, history.NoShows(), history.Registered))
+		}
+
+		username := ""
+		if participant.Username != "" {
+			username = "@" + participant.Username
+		}
+		err = w.Write([]string{
+			participant.Name,
+			strconv.FormatInt(participant.UserID, 10),
+			username,
+			status,
+			formatExportTime(signedUpEvent.JoinedAt, loc),
+			formatExportTime(signedUpEvent.CheckedInAt, loc),
+			strconv.Itoa(history.Registered),
+			strconv.Itoa(history.Attended),
+			strconv.Itoa(history.NoShows()),
+			formatYesNo(history.IsChronicNoShow()),
+		})
+		if err != nil {
+			log.Println("error building attendance CSV:", err)
+			return "Error generating the attendance report. Please try again."
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Println("error building attendance CSV:", err)
+		return "Error generating the attendance report. Please try again."
+	}
+
+	attended := counts[attendanceAttended] + counts[attendanceWalkIn]
+	registered := attended + counts[attendanceNoShow] + counts[attendancePending]
+
+	text := fmt.Sprintf("Attendance report for '%s'\n", event.Name)
+	if !finished {
+		text += "Check-in is still open, so people who haven't arrived yet are not counted as no-shows.\n"
+	}
+	text += fmt.Sprintf("Registered: %d\n", registered)
+	text += fmt.Sprintf("Attended: %d (%s), of which walk-ins: %d\n", attended, formatPercent(attended, registered), counts[attendanceWalkIn])
+	if finished {
+		text += fmt.Sprintf("No-shows: %d (%s)\n", counts[attendanceNoShow], formatPercent(counts[attendanceNoShow], registered))
+	} else {
+		text += fmt.Sprintf("Not checked in yet: %d\n", counts[attendancePending])
+	}
+
+	if len(noShows) > 0 {
+		text += "\nNo-shows:\n"
+		for i, name := range noShows {
+			if i == attendanceReportMaxNames {
+				text += fmt.Sprintf("…and %d more (see the CSV)\n", len(noShows)-attendanceReportMaxNames)
+				break
+			}
+			text += fmt.Sprintf("- %s\n", name)
+		}
+	}
+	if len(chronic) > 0 {
+		text += "\n⚠️ Chronic no-shows across your past events:\n"
+		for _, line := range chronic {
+			text += fmt.Sprintf("- %s\n", line)
+		}
+	}
+
+	_, err = b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:   update.Message.Chat.ID,
+		Document: &models.InputFileUpload{Filename: exportFilename(event, "attendance"), Data: bytes.NewReader(buf.Bytes())},
+		Caption:  fmt.Sprintf("Attendance for '%s' (times in %s)", event.Name, loc),
+	})
+	if err != nil {
+		log.Println("error sending document:", err)
+		text += "\nError sending the CSV. Please try again."
+	}
+	return text
+}
+
+// alertIfChronicNoShow warns the event's organisers when someone who keeps missing their events signs up
+func (p *ParticipantBotHandler) alertIfChronicNoShow(ctx context.Context, event *model.Event, participant *model.Participant) {
+	finishedEvents, err := finishedEventIDs(ctx, p.FirebaseConnector, event.UserID, event.ID)
+	if err != nil {
+		log.Println("error listing organiser events:", err)
+		return
+	}
+
+	history := attendanceHistoryFor(participant, finishedEvents)
+	if !history.IsChronicNoShow() {
+		return
+	}
+
+	organiserBot, err := newOrganiserBot()
+	if err != nil {
+		log.Println("error creating organiser bot:", err)
+		return
+	}
+
+	text := fmt.Sprintf("⚠️ %s [ID: %d] just signed up for '%s'. They missed %d of the %d past events of yours they signed up for.",
+		participant.Name, participant.UserID, event.Name, history.NoShows(), history.Registered)
+	for _, organiserID := range event.MembersWith(model.PermissionViewParticipants) {
+		_, err = organiserBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: organiserID,
+			Text:   text,
+		})
+		if err != nil {
+			log.Printf("error alerting organiser %d: %v", organiserID, err)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package handler
+
+import (
+	"EventBot/model"
+	"EventBot/repo"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Layout organisers use when scheduling a blast, interpreted in their own timezone
+	blastTimeLayout = "2006-01-02 15:04"
+
+	// How often the scheduler looks for blasts that are due
+	blastSchedulerInterval = 30 * time.Second
+
+	// Failed blasts are listed in /scheduledBlasts for this long after failing
+	failedBlastListPeriod = 7 * 24 * time.Hour
+)
+
+// newParticipantBot creates a client for the participant bot so organiser-side code can message participants
+func newParticipantBot() (*bot.Bot, error) {
+	participantBotToken := os.Getenv("PARTICIPANT_BOT_TOKEN")
+	if participantBotToken == "" {
+		return nil, errors.New("participant bot token not configured")
+	}
+
+	return bot.New(participantBotToken)
+}
+
+// formatBlastMessage wraps an organiser's message with the event's name, date and details
+func formatBlastMessage(event *model.Event, message string) string {
+	var eventDetails string
+	if len(event.EventDetails) > 0 {
+		eventDetails += "Event Details:\n"
+		for _, detail := range event.EventDetails {
+			eventDetails += fmt.Sprintf("  - Q: %s\n", detail.Question)
+			eventDetails += fmt.Sprintf("    A: %s\n", detail.Answer)
+		}
+	}
+
+	text := fmt.Sprintf(`Message from the organiser:
+%s
+%s
+Event Name: %s
+Event Date: %s`, message, eventDetails, event.Name, event.EventDate.Format("2006-01-02"))
+	if event.Venue != nil {
+		text += "\nVenue: " + formatVenue(event.Venue)
+	}
+	return text
+}
+
+// sendBlastToParticipants sends a message to every participant of the event through the participant bot
+func (o *OrganiserBotHandler) sendBlastToParticipants(ctx context.Context, event *model.Event, message string) (int, int, error) {
+	return o.sendBlastSkipping(ctx, event, message, make(map[string]bool))
+}
+
+// sendBlastSkipping sends a blast to the event's participants who aren't in notified yet, adding each one it reaches,
+// so a message covering several events reaches everyone once
+func (o *OrganiserBotHandler) sendBlastSkipping(ctx context.Context, event *model.Event, message string, notified map[string]bool) (int, int, error) {
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading participants for event(ID: %s): %w", event.ID, err)
+	}
+
+	participantBot, err := newParticipantBot()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error creating participant bot: %w", err)
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].Name < participants[j].Name
+	})
+
+	fullMessage := formatBlastMessage(event, message)
+	successCount := 0
+	failureCount := 0
+
+	for _, participant := range participants {
+		if notified[participant.ID] {
+			continue
+		}
+		notified[participant.ID] = true
+
+		// Send message using the participant bot
+		_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: participant.UserID,
+			Text:   fullMessage,
+		})
+
+		if err != nil {
+			log.Printf("Error sending message to participant %d: %v", participant.UserID, err)
+			failureCount++
+			continue
+		}
+		successCount++
+
+		// Follow up with the venue pin so the reminder can be opened in a maps app
+		err = sendVenue(ctx, participantBot, participant.UserID, event.Venue)
+		if err != nil {
+			log.Printf("Error sending venue to participant %d: %v", participant.UserID, err)
+		}
+	}
+
+	return successCount, failureCount, nil
+}
+
+func (o *OrganiserBotHandler) handleSelectBlastTiming(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	switch update.Message.Text {
+	case "Send now":
+		event := userState.CurrentEvent
+		message := userState.CurrentBlast.Message
+		resetBlastState(userState)
+
+		successCount, failureCount, err := o.sendBlastToParticipants(ctx, event, message)
+		if err != nil {
+			log.Println(err)
+			return "Error sending the message to participants. Please try again."
+		}
+
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text: fmt.Sprintf("Message sent successfully to %d participants.\n%d participants could not receive the message.",
+				successCount, failureCount),
+			ReplyMarkup: getOrganizerMainMenuKeyboard(),
+		})
+		if err != nil {
+			log.Println("error sending summary message:", err)
+		}
+		return ""
+	case "Schedule for later":
+		userState.State = model.StateSchedulingBlastTime
+		return fmt.Sprintf("When should the message be sent? Reply in the format 'YYYY-MM-DD HH:MM' (timezone: %s).\n\nUse /setTimezone to change your timezone.",
+			o.organiserLocation(ctx, update.Message.From.ID))
+	case "Cancel":
+		resetBlastState(userState)
+		return "Blast cancelled."
+	default:
+		return "Please choose 'Send now' or 'Schedule for later', or 'Cancel' to abort."
+	}
+}
+
+func (o *OrganiserBotHandler) handleSchedulingBlastTime(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	if update.Message.Text == "Cancel" {
+		resetBlastState(userState)
+		return "Blast cancelled."
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	sendAt, errText := parseBlastTime(update.Message.Text, loc)
+	if errText != "" {
+		return errText
+	}
+
+	blast := userState.CurrentBlast
+	blast.SendAt = sendAt
+	blast.Status = model.BlastStatusPending
+
+	blastID, err := o.FirebaseConnector.CreateScheduledBlast(ctx, *blast)
+	resetBlastState(userState)
+	if err != nil {
+		log.Println("error creating scheduled blast:", err)
+		return "Error scheduling the message. Please try again."
+	}
+
+	return fmt.Sprintf("Message scheduled for %s (%s).\nBlast ID: %s\n\nUse /editBlast %s to change it or /cancelBlast %s to cancel it.",
+		sendAt.In(loc).Format(blastTimeLayout), loc, blastID, blastID, blastID)
+}
+
+func (o *OrganiserBotHandler) handleListScheduledBlasts(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionMessage)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view scheduled blasts for this event."
+	}
+
+	blasts, err := o.FirebaseConnector.ListScheduledBlastsByEventID(ctx, eventID)
+	if err != nil {
+		log.Println("error listing scheduled blasts:", err)
+		return "Error retrieving scheduled blasts. Please try again."
+	}
+	failed, err := o.FirebaseConnector.ListFailedScheduledBlastsByEventID(ctx, eventID)
+	if err != nil {
+		log.Println("error listing failed scheduled blasts:", err)
+	}
+	failed = recentlyFailedBlasts(failed, time.Now())
+	if len(blasts) == 0 && len(failed) == 0 {
+		return "There are no scheduled blasts for this event."
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	text := fmt.Sprintf("Scheduled blasts (times in %s):\n", loc)
+	if len(blasts) == 0 {
+		text += "None pending.\n"
+	}
+	for _, blast := range blasts {
+		text += fmt.Sprintf("- %s (Blast ID: %s)\n", blast.SendAt.In(loc).Format(blastTimeLayout), blast.ID)
+		text += fmt.Sprintf("  %s\n", previewText(blast.Message, 80))
+	}
+
+	if len(failed) > 0 {
+		text += "\nFailed in the last 7 days, possibly after reaching some participants. Send them again with /blast if needed:\n"
+		for _, blast := range failed {
+			text += fmt.Sprintf("- %s\n", blast.SendAt.In(loc).Format(blastTimeLayout))
+			text += fmt.Sprintf("  %s\n", previewText(blast.Message, 80))
+		}
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleEditScheduledBlast(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	blast, errText := o.readEditableBlast(ctx, update.Message.Text, update.Message.From.ID)
+	if errText != "" {
+		userState.State = model.StateIdle
+		return errText
+	}
+
+	userState.CurrentBlast = blast
+	userState.State = model.StateSelectScheduledBlastEditOption
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	return fmt.Sprintf("Scheduled for %s (%s):\n%s\n\nChoose what you want to edit:\n"+
+		"1. Message\n"+
+		"2. Send Time\n"+
+		"3. Cancel Edit", blast.SendAt.In(loc).Format(blastTimeLayout), loc, blast.Message)
+}
+
+func (o *OrganiserBotHandler) handleSelectScheduledBlastEditOption(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	switch update.Message.Text {
+	case "1":
+		userState.State = model.StateEditScheduledBlastMessage
+		return "Enter the new message:"
+	case "2":
+		userState.State = model.StateEditScheduledBlastTime
+		return fmt.Sprintf("Enter the new send time in the format 'YYYY-MM-DD HH:MM' (timezone: %s):",
+			o.organiserLocation(ctx, update.Message.From.ID))
+	case "3":
+		resetBlastState(userState)
+		return "Blast editing cancelled."
+	default:
+		return "Invalid option. Please choose 1-3."
+	}
+}
+
+func (o *OrganiserBotHandler) handleEditScheduledBlastMessage(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	blast := userState.CurrentBlast
+	blast.Message = update.Message.Text
+	resetBlastState(userState)
+
+	err := o.FirebaseConnector.UpdatePendingScheduledBlast(ctx, *blast)
+	if errors.Is(err, model.ErrBlastNotPending) {
+		return "This blast has already been sent or cancelled and can no longer be edited."
+	} else if err != nil {
+		log.Println("error updating scheduled blast:", err)
+		return "Error updating the scheduled blast. Please try again."
+	}
+	return "Scheduled blast message updated."
+}
+
+func (o *OrganiserBotHandler) handleEditScheduledBlastTime(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	sendAt, errText := parseBlastTime(update.Message.Text, loc)
+	if errText != "" {
+		return errText
+	}
+
+	blast := userState.CurrentBlast
+	blast.SendAt = sendAt
+	resetBlastState(userState)
+
+	err := o.FirebaseConnector.UpdatePendingScheduledBlast(ctx, *blast)
+	if errors.Is(err, model.ErrBlastNotPending) {
+		return "This blast has already been sent or cancelled and can no longer be edited."
+	} else if err != nil {
+		log.Println("error updating scheduled blast:", err)
+		return "Error updating the scheduled blast. Please try again."
+	}
+	return fmt.Sprintf("Scheduled blast will now be sent at %s (%s).", sendAt.In(loc).Format(blastTimeLayout), loc)
+}
+
+func (o *OrganiserBotHandler) handleCancelScheduledBlast(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	blast, errText := o.readEditableBlast(ctx, update.Message.Text, update.Message.From.ID)
+	if errText != "" {
+		return errText
+	}
+
+	blast.Status = model.BlastStatusCancelled
+	err := o.FirebaseConnector.UpdatePendingScheduledBlast(ctx, *blast)
+	if errors.Is(err, model.ErrBlastNotPending) {
+		return "This blast has already been sent or cancelled."
+	} else if err != nil {
+		log.Println("error cancelling scheduled blast:", err)
+		return "Error cancelling the scheduled blast. Please try again."
+	}
+	return fmt.Sprintf("Scheduled blast %s has been cancelled.", blast.ID)
+}
+
+func (o *OrganiserBotHandler) handleSettingTimezone(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	zone := strings.TrimSpace(update.Message.Text)
+	loc, err := time.LoadLocation(zone)
+	if err != nil || zone == "" {
+		return fmt.Sprintf("Unknown timezone '%s'. Please use a name such as Asia/Singapore or Europe/London.", zone)
+	}
+
+	organiser, err := o.FirebaseConnector.ReadOrganiser(ctx, update.Message.From.ID)
+	if err != nil {
+		log.Println("error reading organiser:", err)
+		return "Error updating your timezone. Please try again."
+	}
+	if organiser == nil {
+		organiser = &model.Organiser{UserID: update.Message.From.ID}
+	}
+	organiser.Timezone = loc.String()
+
+	err = o.FirebaseConnector.UpdateOrganiser(ctx, *organiser)
+	if err != nil {
+		log.Println("error updating organiser:", err)
+		return "Error updating your timezone. Please try again."
+	}
+	return fmt.Sprintf("Your timezone has been set to %s.", loc)
+}
+
+// readEditableBlast loads a pending blast and checks that the user may manage it; errText is set on failure
+func (o *OrganiserBotHandler) readEditableBlast(ctx context.Context, blastID string, userID int64) (*model.ScheduledBlast, string) {
+	blast, err := o.FirebaseConnector.ReadScheduledBlast(ctx, blastID)
+	if err != nil {
+		log.Println("error reading scheduled blast:", err)
+		return nil, fmt.Sprintf("Error finding scheduled blast with ID '%s'. Please check the ID and try again.", blastID)
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, blast.EventID, userID, model.PermissionMessage)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return nil, "Error checking event ownership. Please try again."
+	}
+	if !allowed {
+		return nil, "You don't have permission to manage scheduled blasts for this event."
+	}
+
+	if blast.Status != model.BlastStatusPending {
+		return nil, "This blast has already been sent or cancelled."
+	}
+	return blast, ""
+}
+
+// organiserLocation returns the organiser's configured timezone, falling back to DEFAULT_TIMEZONE and then UTC
+func (o *OrganiserBotHandler) organiserLocation(ctx context.Context, userID int64) *time.Location {
+	return locationForOrganiser(ctx, o.FirebaseConnector, userID)
+}
+
+// locationForOrganiser loads an organiser's timezone so either bot can show times the way the organiser entered them
+func locationForOrganiser(ctx context.Context, fc repo.FirestoreConnector, userID int64) *time.Location {
+	organiser, err := fc.ReadOrganiser(ctx, userID)
+	if err != nil {
+		log.Println("error reading organiser:", err)
+	}
+	if organiser != nil && organiser.Timezone != "" {
+		if loc, err := time.LoadLocation(organiser.Timezone); err == nil {
+			return loc
+		}
+	}
+
+	if zone := os.Getenv("DEFAULT_TIMEZONE"); zone != "" {
+		if loc, err := time.LoadLocation(zone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// parseBlastTime parses a send time in the organiser's timezone and makes sure it is in the future; errText is set on failure
+func parseBlastTime(text string, loc *time.Location) (time.Time, string) {
+	sendAt, err := time.ParseInLocation(blastTimeLayout, strings.TrimSpace(text), loc)
+	if err != nil {
+		return time.Time{}, "Invalid time format. Please use 'YYYY-MM-DD HH:MM' (e.g., 2023-12-25 18:30)."
+	}
+	if !sendAt.After(time.Now()) {
+		return time.Time{}, "The send time must be in the future. Please enter a later time."
+	}
+	return sendAt.UTC(), ""
+}
+
+func resetBlastState(userState *model.UserState) {
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.CurrentBlast = nil
+}
+
+// previewText shortens text to at most limit runes for list views
+func previewText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
+
+// RunBlastScheduler sends scheduled blasts once they are due until ctx is cancelled.
+// Pending blasts live in Firestore, so any that came due while the bot was down are sent on the first tick.
+// Blasts that were part-way through sending when the bot stopped are marked failed and their organisers told.
+func (o *OrganiserBotHandler) RunBlastScheduler(ctx context.Context, b *bot.Bot) {
+	o.failInterruptedBlasts(ctx, b)
+
+	ticker := time.NewTicker(blastSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		o.dispatchDueBlasts(ctx, b)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recentlyFailedBlasts keeps the blasts that failed within failedBlastListPeriod, so old failures drop off /scheduledBlasts
+func recentlyFailedBlasts(blasts []model.ScheduledBlast, now time.Time) []model.ScheduledBlast {
+	recent := blasts[:0]
+	for _, blast := range blasts {
+		failedAt := blast.FailedAt
+		if failedAt.IsZero() {
+			failedAt = blast.SendAt
+		}
+		if now.Sub(failedAt) <= failedBlastListPeriod {
+			recent = append(recent, blast)
+		}
+	}
+	return recent
+}
+
+func (o *OrganiserBotHandler) failInterruptedBlasts(ctx context.Context, b *bot.Bot) {
+	blasts, err := o.FirebaseConnector.FailInterruptedScheduledBlasts(ctx)
+	if err != nil {
+		log.Println("error failing interrupted scheduled blasts:", err)
+		return
+	}
+
+	for _, blast := range blasts {
+		eventName := blast.EventID
+		if event, err := o.FirebaseConnector.ReadEvent(ctx, blast.EventID); err == nil {
+			eventName = event.Name
+		}
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: blast.CreatedBy,
+			Text: fmt.Sprintf("Your scheduled message for '%s' was interrupted while it was being sent, so some participants may not have received it:\n%s\n\nPlease check with /scheduledBlasts %s and send it again with /blast if needed.",
+				eventName, previewText(blast.Message, 80), blast.EventID),
+		})
+		if err != nil {
+			log.Println("error sending scheduled blast summary:", err)
+		}
+	}
+}
+
+func (o *OrganiserBotHandler) dispatchDueBlasts(ctx context.Context, b *bot.Bot) {
+	blasts, err := o.FirebaseConnector.ListDueScheduledBlasts(ctx, time.Now())
+	if err != nil {
+		log.Println("error listing due scheduled blasts:", err)
+		return
+	}
+
+	for _, due := range blasts {
+		// Claim the blast first so an edit or cancel racing with us can't be sent twice
+		blast, err := o.FirebaseConnector.ClaimScheduledBlast(ctx, due.ID)
+		if err != nil {
+			if !errors.Is(err, model.ErrBlastNotPending) {
+				log.Printf("error claiming scheduled blast %s: %v", due.ID, err)
+			}
+			continue
+		}
+
+		event, err := o.FirebaseConnector.ReadEvent(ctx, blast.EventID)
+		if err != nil {
+			log.Printf("error reading event for scheduled blast %s: %v", blast.ID, err)
+			blast.Status = model.BlastStatusCancelled
+			if err := o.FirebaseConnector.UpdateScheduledBlast(ctx, *blast); err != nil {
+				log.Println("error updating scheduled blast:", err)
+			}
+			continue
+		}
+		event.ID = blast.EventID
+
+		// Participants of cancelled events have already been told, so drop anything still scheduled
+		if event.CurrentStatus() == model.EventStatusCancelled || event.IsDeleted() {
+			blast.Status = model.BlastStatusCancelled
+			if err := o.FirebaseConnector.UpdateScheduledBlast(ctx, *blast); err != nil {
+				log.Println("error updating scheduled blast:", err)
+			}
+			continue
+		}
+
+		var summaryText string
+		successCount, failureCount, err := o.sendBlastToParticipants(ctx, event, blast.Message)
+		if err != nil {
+			log.Printf("error sending scheduled blast %s: %v", blast.ID, err)
+			blast.Status = model.BlastStatusFailed
+			blast.FailedAt = time.Now().UTC()
+			summaryText = fmt.Sprintf("Your scheduled message for '%s' could not be sent. Please send it manually with /blast.", event.Name)
+		} else {
+			blast.Status = model.BlastStatusSent
+			summaryText = fmt.Sprintf("Your scheduled message for '%s' has been sent to %d participants.\n%d participants could not receive the message.",
+				event.Name, successCount, failureCount)
+		}
+
+		blast.SentAt = time.Now().UTC()
+		blast.SuccessCount = successCount
+		blast.FailureCount = failureCount
+		if err := o.FirebaseConnector.UpdateScheduledBlast(ctx, *blast); err != nil {
+			log.Println("error updating scheduled blast:", err)
+		}
+
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: blast.CreatedBy,
+			Text:   summaryText,
+		})
+		if err != nil {
+			log.Println("error sending scheduled blast summary:", err)
+		}
+	}
+}
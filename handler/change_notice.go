@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// describeEventChange renders one changed field with its old and new values for participants
+func describeEventChange(field string, oldValue string, newValue string) string {
+	if oldValue == "" {
+		oldValue = "(none)"
+	}
+	if newValue == "" {
+		newValue = "(none)"
+	}
+	return fmt.Sprintf("%s\n  Before: %s\n  Now: %s", field, oldValue, newValue)
+}
+
+// describeVenueForChange renders a venue on one line, or "" if the event has none
+func describeVenueForChange(venue *model.Venue) string {
+	if venue == nil {
+		return ""
+	}
+	return strings.ReplaceAll(formatVenue(venue), "\n", ", ")
+}
+
+// offerChangeNotice finishes an /editEvent change and, when participants can see the event, asks whether to tell them.
+// Participants of the later sessions the change was copied to are told too.
+func (o *OrganiserBotHandler) offerChangeNotice(userState *model.UserState, event *model.Event, occurrences []model.Event, savedText string, change string) string {
+	// The edit is done, so clear what it kept: the detail being edited and the series scope
+	userState.LastQuestion = ""
+	userState.TempOptions = nil
+	userState.PendingChange = ""
+	userState.ChangedOccurrenceIDs = nil
+	recipients := changeNoticeRecipients(event, occurrences)
+	if change == "" || event.CurrentStatus() != model.EventStatusPublished || recipients == 0 {
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return savedText
+	}
+
+	userState.CurrentEvent = event
+	userState.PendingChange = change
+	for _, occurrence := range occurrences {
+		userState.ChangedOccurrenceIDs = append(userState.ChangedOccurrenceIDs, occurrence.ID)
+	}
+	userState.State = model.StateConfirmChangeNotice
+	return fmt.Sprintf("%s\n\nDo you want to tell the %d participants about this change? They will receive:\n\n%s\n\nReply 'yes' to notify them or 'no' to skip.",
+		savedText, recipients, changeNoticeText(event, change))
+}
+
+// changeNoticeRecipients counts the people signed up for the event or any of the occurrences, counting each person once
+func changeNoticeRecipients(event *model.Event, occurrences []model.Event) int {
+	recipients := make(map[string]bool)
+	for _, participantID := range event.Participants {
+		recipients[participantID] = true
+	}
+	for _, occurrence := range occurrences {
+		for _, participantID := range occurrence.Participants {
+			recipients[participantID] = true
+		}
+	}
+	return len(recipients)
+}
+
+// changeNoticeText is the message participants receive about a change to the event
+func changeNoticeText(event *model.Event, change string) string {
+	return fmt.Sprintf("📝 '%s' has been updated:\n%s", event.Name, change)
+}
+
+func (o *OrganiserBotHandler) handleConfirmChangeNotice(ctx context.Context, text string, userState *model.UserState) string {
+	event := userState.CurrentEvent
+	change := userState.PendingChange
+	occurrenceIDs := userState.ChangedOccurrenceIDs
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.PendingChange = ""
+	userState.ChangedOccurrenceIDs = nil
+
+	if strings.ToLower(strings.TrimSpace(text)) != "yes" {
+		return "Participants have not been notified."
+	}
+
+	notified := make(map[string]bool)
+	successCount, failureCount, err := o.sendBlastSkipping(ctx, event, changeNoticeText(event, change), notified)
+	if err != nil {
+		log.Println("error sending change notice:", err)
+		return "Error notifying participants. Please tell them with /blast " + event.ID + "."
+	}
+
+	// People who only signed up for later sessions of the series hear about it with their own session's details
+	for _, occurrenceID := range occurrenceIDs {
+		occurrence, err := o.FirebaseConnector.ReadEvent(ctx, occurrenceID)
+		if err != nil {
+			log.Println("error reading event:", err)
+			continue
+		}
+		occurrence.ID = occurrenceID
+		success, failure, err := o.sendBlastSkipping(ctx, occurrence, changeNoticeText(occurrence, change), notified)
+		if err != nil {
+			log.Println("error sending change notice:", err)
+			continue
+		}
+		successCount += success
+		failureCount += failure
+	}
+	return fmt.Sprintf("%d participants have been told about the change.\n%d participants could not receive the message.", successCount, failureCount)
+}
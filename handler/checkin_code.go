@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Rotating codes keep the same 4-digit format participants already know
+	checkInCodeDigits = 4
+
+	minCheckInCodePeriod = 15
+	maxCheckInCodePeriod = 3600
+
+	// A live code message stops updating after this long if nobody stops it
+	maxLiveCheckInCodeDuration = 12 * time.Hour
+)
+
+// liveCheckInCode is a live code message being kept up to date
+type liveCheckInCode struct {
+	stop context.CancelFunc
+}
+
+// Live code messages being kept up to date, keyed by the organiser's chat ID
+var (
+	liveCheckInCodes   = make(map[int64]*liveCheckInCode)
+	liveCheckInCodesMu sync.Mutex
+)
+
+// hasCheckInCode reports whether the organiser has set up a static or rotating check-in code
+func hasCheckInCode(event *model.Event) bool {
+	return event.CheckInCode != "" || isRotatingCheckInCode(event)
+}
+
+func isRotatingCheckInCode(event *model.Event) bool {
+	return event.CheckInCodePeriod > 0 && event.CheckInCodeSecret != ""
+}
+
+// isValidCheckInCode checks a code against the static code, or the current and previous rotating windows
+func isValidCheckInCode(event *model.Event, code string, now time.Time) bool {
+	if !isRotatingCheckInCode(event) {
+		return event.CheckInCode != "" && code == event.CheckInCode
+	}
+
+	counter := checkInCodeCounter(event, now)
+	if hmac.Equal([]byte(code), []byte(rotatingCheckInCode(event, counter))) {
+		return true
+	}
+	// Accept the previous window so a code read just before it rotates still works
+	return counter > 0 && hmac.Equal([]byte(code), []byte(rotatingCheckInCode(event, counter-1)))
+}
+
+// currentCheckInCode returns the code valid right now and when it stops being shown
+func currentCheckInCode(event *model.Event, now time.Time) (string, time.Time) {
+	if !isRotatingCheckInCode(event) {
+		return event.CheckInCode, time.Time{}
+	}
+
+	counter := checkInCodeCounter(event, now)
+	period := int64(event.CheckInCodePeriod)
+	return rotatingCheckInCode(event, counter), time.Unix(int64(counter+1)*period, 0)
+}
+
+func checkInCodeCounter(event *model.Event, now time.Time) uint64 {
+	return uint64(now.Unix() / int64(event.CheckInCodePeriod))
+}
+
+// rotatingCheckInCode computes the TOTP (RFC 6238) code for a time step
+func rotatingCheckInCode(event *model.Event, counter uint64) string {
+	secret, err := base64.StdEncoding.DecodeString(event.CheckInCodeSecret)
+	if err != nil {
+		log.Println("error decoding check-in code secret:", err)
+		return ""
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < checkInCodeDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", checkInCodeDigits, value%modulo)
+}
+
+func newCheckInCodeSecret() (string, error) {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(secret), nil
+}
+
+// describeCheckInCode summarises an event's check-in code for organiser listings
+func describeCheckInCode(event *model.Event) string {
+	if isRotatingCheckInCode(event) {
+		return fmt.Sprintf("Rotating every %d seconds (use /checkInCode %s)", event.CheckInCodePeriod, event.ID)
+	}
+	if event.CheckInCode != "" {
+		return event.CheckInCode
+	}
+	return "Not set (use /setCheckInCode)"
+}
+
+func (o *OrganiserBotHandler) handleSettingCheckInCodePeriod(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	period, err := strconv.Atoi(update.Message.Text)
+	if err != nil || period < minCheckInCodePeriod || period > maxCheckInCodePeriod {
+		return fmt.Sprintf("Please enter a number of seconds between %d and %d (e.g., 60).", minCheckInCodePeriod, maxCheckInCodePeriod)
+	}
+
+	secret, err := newCheckInCodeSecret()
+	if err != nil {
+		log.Println("error generating check-in code secret:", err)
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return "Error setting up the rotating check-in code. Please try again."
+	}
+
+	event := userState.CurrentEvent
+	event.CheckInCode = ""
+	event.CheckInCodeSecret = secret
+	event.CheckInCodePeriod = period
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+
+	err = o.FirebaseConnector.UpdateEvent(ctx, event.ID, *event)
+	if err != nil {
+		log.Println("error updating event:", err)
+		return "Error updating check-in code. Please try again."
+	}
+	return fmt.Sprintf("Event '%s' now uses a check-in code that changes every %d seconds.\n\n"+
+		"Use /checkInCode %s to see the current code or /liveCheckInCode %s to post one that keeps itself up to date.",
+		event.Name, period, event.ID, event.ID)
+}
+
+func (o *OrganiserBotHandler) handleShowCheckInCode(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	event, errText := o.readCheckInCodeEvent(ctx, update.Message.Text, update.Message.From.ID)
+	if errText != "" {
+		return errText
+	}
+
+	code, expiresAt := currentCheckInCode(event, time.Now())
+	if expiresAt.IsZero() {
+		return fmt.Sprintf("Check-in code for '%s': %s", event.Name, code)
+	}
+	return fmt.Sprintf("Check-in code for '%s': %s\nChanges in %d seconds.", event.Name, code, int(time.Until(expiresAt).Seconds())+1)
+}
+
+func (o *OrganiserBotHandler) handleLiveCheckInCode(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	event, errText := o.readCheckInCodeEvent(ctx, update.Message.Text, update.Message.From.ID)
+	if errText != "" {
+		return errText
+	}
+	if !isRotatingCheckInCode(event) {
+		return fmt.Sprintf("Event '%s' uses a fixed check-in code: %s", event.Name, event.CheckInCode)
+	}
+
+	chatID := update.Message.Chat.ID
+	code, expiresAt := currentCheckInCode(event, time.Now())
+	msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      formatLiveCheckInCode(event, code, expiresAt),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		log.Println("error sending live check-in code:", err)
+		return "Error posting the live check-in code. Please try again."
+	}
+
+	// Replace any live code already running in this chat
+	liveCtx, cancel := context.WithTimeout(ctx, maxLiveCheckInCodeDuration)
+	live := &liveCheckInCode{stop: cancel}
+	liveCheckInCodesMu.Lock()
+	if running, ok := liveCheckInCodes[chatID]; ok {
+		running.stop()
+	}
+	liveCheckInCodes[chatID] = live
+	liveCheckInCodesMu.Unlock()
+
+	go o.keepCheckInCodeLive(liveCtx, b, chatID, msg.ID, live, event, expiresAt)
+	return "The code above updates itself as it rotates. Send /stopLiveCheckInCode to stop it."
+}
+
+// keepCheckInCodeLive edits the live code message at every rotation until ctx ends,
+// or until the event stops using rotating codes or can no longer be checked in to
+func (o *OrganiserBotHandler) keepCheckInCodeLive(ctx context.Context, b *bot.Bot, chatID int64, messageID int, live *liveCheckInCode, event *model.Event, next time.Time) {
+	defer func() {
+		live.stop()
+		liveCheckInCodesMu.Lock()
+		if liveCheckInCodes[chatID] == live {
+			delete(liveCheckInCodes, chatID)
+		}
+		liveCheckInCodesMu.Unlock()
+
+		_, err := b.EditMessageText(context.Background(), &bot.EditMessageTextParams{
+			ChatID:    chatID,
+			MessageID: messageID,
+			Text:      fmt.Sprintf("Live check-in code for '%s' has stopped.\nUse /checkInCode %s to see the current code.", event.Name, event.ID),
+		})
+		if err != nil {
+			log.Println("error editing live check-in code:", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		// Pick up changes to the code settings, and stop once the code can't be used
+		latest, err := o.FirebaseConnector.ReadEvent(ctx, event.ID)
+		if err != nil {
+			log.Println("error reading event for live check-in code:", err)
+			return
+		}
+		latest.ID = event.ID
+		if !isRotatingCheckInCode(latest) || latest.IsDeleted() {
+			return
+		}
+		switch latest.CurrentStatus() {
+		case model.EventStatusCancelled, model.EventStatusCompleted:
+			return
+		}
+		event = latest
+
+		var code string
+		code, next = currentCheckInCode(event, time.Now())
+		_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    chatID,
+			MessageID: messageID,
+			Text:      formatLiveCheckInCode(event, code, next),
+			ParseMode: "HTML",
+		})
+		if err != nil {
+			log.Println("error editing live check-in code:", err)
+		}
+	}
+}
+
+func formatLiveCheckInCode(event *model.Event, code string, expiresAt time.Time) string {
+	return fmt.Sprintf("Check-in code for '%s':\n\n<b>%s</b>\n\nChanges every %d seconds (next change at %s UTC).",
+		html.EscapeString(event.Name), code, event.CheckInCodePeriod, expiresAt.UTC().Format("15:04:05"))
+}
+
+// stopLiveCheckInCode stops the live code message in a chat, reporting whether one was running
+func stopLiveCheckInCode(chatID int64) bool {
+	liveCheckInCodesMu.Lock()
+	defer liveCheckInCodesMu.Unlock()
+
+	live, ok := liveCheckInCodes[chatID]
+	if ok {
+		live.stop()
+		delete(liveCheckInCodes, chatID)
+	}
+	return ok
+}
+
+// readCheckInCodeEvent loads an event whose check-in code the user may see; errText is set on failure
+func (o *OrganiserBotHandler) readCheckInCodeEvent(ctx context.Context, eventID string, userID int64) (*model.Event, string) {
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionCheckIn)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return nil, fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return nil, "You don't have permission to see the check-in code for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return nil, fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	if !hasCheckInCode(event) {
+		return nil, fmt.Sprintf("Event '%s' has no check-in code yet. Use /setCheckInCode to set one.", event.Name)
+	}
+	return event, ""
+}
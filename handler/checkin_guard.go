@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"github.com/go-telegram/bot"
+)
+
+const (
+	// Failed code attempts allowed before each further attempt has to wait
+	freeCheckInAttempts = 2
+
+	// Wait after the first delayed attempt, doubling with every further failure
+	checkInBackoffBase = 30 * time.Second
+
+	// Failed attempts after which the user is locked out for checkInLockoutDuration
+	checkInLockoutAfter    = 8
+	checkInLockoutDuration = time.Hour
+
+	// Organisers are alerted when an event sees this many failures within the window
+	eventFailureWindow         = 10 * time.Minute
+	eventFailureAlertThreshold = 20
+)
+
+// newOrganiserBot creates a client for the organiser bot so participant-side code can alert organisers
+func newOrganiserBot() (*bot.Bot, error) {
+	organiserBotToken := os.Getenv("ORGANISER_BOT_TOKEN")
+	if organiserBotToken == "" {
+		return nil, errors.New("organiser bot token not configured")
+	}
+
+	return bot.New(organiserBotToken)
+}
+
+// checkInBackoff returns how long a user must wait after their latest failed attempt
+func checkInBackoff(failedCount int) time.Duration {
+	if failedCount >= checkInLockoutAfter {
+		return checkInLockoutDuration
+	}
+	if failedCount <= freeCheckInAttempts {
+		return 0
+	}
+	return checkInBackoffBase * time.Duration(math.Pow(2, float64(failedCount-freeCheckInAttempts-1)))
+}
+
+// checkInLockedText returns a message telling the user to wait if they are backed off or locked out, or "" if they may try
+func (p *ParticipantBotHandler) checkInLockedText(ctx context.Context, eventID string, userID int64) string {
+	attempt, err := p.FirebaseConnector.ReadCheckInAttempt(ctx, eventID, userID)
+	if err != nil {
+		log.Println("error reading check-in attempts:", err)
+		return ""
+	}
+
+	wait := time.Until(attempt.LockedUntil)
+	if wait <= 0 {
+		return ""
+	}
+	if attempt.FailedCount >= checkInLockoutAfter {
+		return fmt.Sprintf("Check-in is locked after too many incorrect codes. Please try again in %s or ask the event organizer for help.", formatWait(wait))
+	}
+	return fmt.Sprintf("Too many incorrect codes. Please wait %s before trying again.", formatWait(wait))
+}
+
+// recordFailedCheckIn tracks a wrong code for the user and event and returns the message to show them
+func (p *ParticipantBotHandler) recordFailedCheckIn(ctx context.Context, event *model.Event, userID int64) string {
+	now := time.Now()
+
+	attempt, err := p.FirebaseConnector.ReadCheckInAttempt(ctx, event.ID, userID)
+	if err != nil {
+		log.Println("error reading check-in attempts:", err)
+		return "Incorrect check-in code. Please try again or contact the event organizer."
+	}
+
+	attempt.FailedCount++
+	attempt.LastFailedAt = now.UTC()
+	wait := checkInBackoff(attempt.FailedCount)
+	attempt.LockedUntil = now.Add(wait).UTC()
+
+	err = p.FirebaseConnector.UpdateCheckInAttempt(ctx, *attempt)
+	if err != nil {
+		log.Println("error updating check-in attempts:", err)
+	}
+
+	action := model.AuditActionCheckInFailed
+	if attempt.FailedCount == checkInLockoutAfter {
+		action = model.AuditActionCheckInLockout
+	}
+	err = p.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      event.ID,
+		Action:       action,
+		ActorID:      userID,
+		TargetUserID: userID,
+		Details:      fmt.Sprintf("Failed attempt %d", attempt.FailedCount),
+		At:           now.UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+
+	p.trackEventCheckInFailures(ctx, event, now)
+
+	switch {
+	case attempt.FailedCount >= checkInLockoutAfter:
+		return fmt.Sprintf("Incorrect check-in code. Check-in is now locked for %s. Please ask the event organizer for help.", formatWait(wait))
+	case wait > 0:
+		return fmt.Sprintf("Incorrect check-in code. Please wait %s before trying again.", formatWait(wait))
+	default:
+		return "Incorrect check-in code. Please try again or contact the event organizer."
+	}
+}
+
+// resetCheckInAttempts clears the user's failed attempts after a successful check-in
+func (p *ParticipantBotHandler) resetCheckInAttempts(ctx context.Context, eventID string, userID int64) {
+	attempt, err := p.FirebaseConnector.ReadCheckInAttempt(ctx, eventID, userID)
+	if err != nil {
+		log.Println("error reading check-in attempts:", err)
+		return
+	}
+	if attempt.FailedCount == 0 {
+		return
+	}
+
+	attempt.FailedCount = 0
+	attempt.LockedUntil = time.Time{}
+	err = p.FirebaseConnector.UpdateCheckInAttempt(ctx, *attempt)
+	if err != nil {
+		log.Println("error updating check-in attempts:", err)
+	}
+}
+
+// trackEventCheckInFailures counts a failure against the event and alerts its organisers once per window when it looks like guessing
+func (p *ParticipantBotHandler) trackEventCheckInFailures(ctx context.Context, event *model.Event, now time.Time) {
+	guard, err := p.FirebaseConnector.RecordEventCheckInFailure(ctx, event.ID, eventFailureWindow, now)
+	if err != nil {
+		log.Println("error recording event check-in failure:", err)
+		return
+	}
+
+	if guard.WindowFailures < eventFailureAlertThreshold || !guard.LastAlertAt.Before(guard.WindowStart) {
+		return
+	}
+
+	guard.LastAlertAt = now.UTC()
+	err = p.FirebaseConnector.UpdateEventCheckInGuard(ctx, *guard)
+	if err != nil {
+		log.Println("error updating event check-in guard:", err)
+	}
+
+	organiserBot, err := newOrganiserBot()
+	if err != nil {
+		log.Println("error creating organiser bot:", err)
+		return
+	}
+
+	text := fmt.Sprintf("⚠️ Event '%s' has had %d incorrect check-in code attempts in the last %d minutes. "+
+		"The code may have leaked; consider changing it with /setCheckInCode %s.",
+		event.Name, guard.WindowFailures, int(eventFailureWindow.Minutes()), event.ID)
+	for _, organiserID := range event.MembersWith(model.PermissionCheckIn) {
+		_, err = organiserBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: organiserID,
+			Text:   text,
+		})
+		if err != nil {
+			log.Printf("error alerting organiser %d: %v", organiserID, err)
+		}
+	}
+}
+
+// formatWait renders a wait time rounded up to whole seconds or minutes
+func formatWait(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d seconds", int(math.Ceil(d.Seconds())))
+	}
+	minutes := int(math.Ceil(d.Minutes()))
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
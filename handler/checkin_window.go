@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// Layout organisers use for check-in window times, interpreted in their own timezone
+const checkInWindowLayout = "2006-01-02 15:04"
+
+// effectiveCheckInWindows returns the event's check-in windows, defaulting to the whole event date in loc
+func effectiveCheckInWindows(event *model.Event, loc *time.Location) []model.CheckInWindow {
+	if len(event.CheckInWindows) > 0 {
+		return event.CheckInWindows
+	}
+
+	year, month, day := event.EventDate.Date()
+	opensAt := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return []model.CheckInWindow{{OpensAt: opensAt, ClosesAt: opensAt.AddDate(0, 0, 1)}}
+}
+
+// checkInClosesAt returns when the event's last check-in window closes, which is when the event counts as over
+func checkInClosesAt(event *model.Event, loc *time.Location) time.Time {
+	var closesAt time.Time
+	for _, window := range effectiveCheckInWindows(event, loc) {
+		if window.ClosesAt.After(closesAt) {
+			closesAt = window.ClosesAt
+		}
+	}
+	return closesAt
+}
+
+// checkInClosedText returns why check-in is not open at now, or "" if it is
+func checkInClosedText(event *model.Event, loc *time.Location, now time.Time) string {
+	if blockedText := checkInBlockedText(event); blockedText != "" {
+		return blockedText
+	}
+	windows := effectiveCheckInWindows(event, loc)
+
+	var next *model.CheckInWindow
+	for i, window := range windows {
+		if !now.Before(window.OpensAt) && now.Before(window.ClosesAt) {
+			return ""
+		}
+		if now.Before(window.OpensAt) && (next == nil || window.OpensAt.Before(next.OpensAt)) {
+			next = &windows[i]
+		}
+	}
+
+	if next != nil {
+		return fmt.Sprintf("Check-in for '%s' is not open yet. It opens at %s (%s).",
+			event.Name, next.OpensAt.In(loc).Format(checkInWindowLayout), loc)
+	}
+	return fmt.Sprintf("Check-in for '%s' has closed.", event.Name)
+}
+
+func (o *OrganiserBotHandler) handleSettingCheckInWindowsEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		userState.State = model.StateIdle
+		return "You don't have permission to change the check-in windows for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	userState.CurrentEvent = event
+	userState.State = model.StateEnteringCheckInWindows
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	text := fmt.Sprintf("Current check-in windows for '%s':\n%s\n", event.Name, formatCheckInWindows(event, loc))
+	text += fmt.Sprintf("Send the new windows, one per line, as 'YYYY-MM-DD HH:MM - YYYY-MM-DD HH:MM' (times in %s).\n", loc)
+	text += "For example:\n2023-12-24 18:00 - 2023-12-25 02:00\n\n"
+	text += "Send 'default' to only allow check-in on the event date, or 'Cancel' to keep the current windows."
+	return text
+}
+
+func (o *OrganiserBotHandler) handleEnteringCheckInWindows(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	input := strings.TrimSpace(update.Message.Text)
+	if input == "Cancel" {
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return "Check-in windows unchanged."
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	var windows []model.CheckInWindow
+	if !strings.EqualFold(input, "default") {
+		var errText string
+		windows, errText = parseCheckInWindows(input, loc)
+		if errText != "" {
+			return errText
+		}
+	}
+
+	event := userState.CurrentEvent
+	event.CheckInWindows = windows
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+
+	err := o.FirebaseConnector.UpdateEvent(ctx, event.ID, *event)
+	if err != nil {
+		log.Println("error updating event:", err)
+		return "Error updating check-in windows. Please try again."
+	}
+	return fmt.Sprintf("Check-in windows for '%s' updated:\n%s", event.Name, formatCheckInWindows(event, loc))
+}
+
+// parseCheckInWindows reads one window per line in loc and returns them sorted by opening time; errText is set on failure
+func parseCheckInWindows(text string, loc *time.Location) ([]model.CheckInWindow, string) {
+	var windows []model.CheckInWindow
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, " - ")
+		if len(parts) != 2 {
+			return nil, fmt.Sprintf("Could not read '%s'. Please use 'YYYY-MM-DD HH:MM - YYYY-MM-DD HH:MM'.", line)
+		}
+		opensAt, err := time.ParseInLocation(checkInWindowLayout, strings.TrimSpace(parts[0]), loc)
+		if err != nil {
+			return nil, fmt.Sprintf("Invalid opening time in '%s'. Please use 'YYYY-MM-DD HH:MM' (e.g., 2023-12-25 18:30).", line)
+		}
+		closesAt, err := time.ParseInLocation(checkInWindowLayout, strings.TrimSpace(parts[1]), loc)
+		if err != nil {
+			return nil, fmt.Sprintf("Invalid closing time in '%s'. Please use 'YYYY-MM-DD HH:MM' (e.g., 2023-12-25 23:00).", line)
+		}
+		if !closesAt.After(opensAt) {
+			return nil, fmt.Sprintf("The window '%s' closes before it opens. Please check the times.", line)
+		}
+
+		windows = append(windows, model.CheckInWindow{OpensAt: opensAt.UTC(), ClosesAt: closesAt.UTC()})
+	}
+
+	if len(windows) == 0 {
+		return nil, "Please send at least one check-in window, 'default' or 'Cancel'."
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].OpensAt.Before(windows[j].OpensAt)
+	})
+	return windows, ""
+}
+
+// formatCheckInWindows lists an event's check-in windows, noting when the event date default applies
+func formatCheckInWindows(event *model.Event, loc *time.Location) string {
+	if len(event.CheckInWindows) == 0 {
+		return fmt.Sprintf("- All day on %s (default)\n", event.EventDate.Format("2006-01-02"))
+	}
+
+	var text string
+	for _, window := range event.CheckInWindows {
+		text += fmt.Sprintf("- %s to %s\n", window.OpensAt.In(loc).Format(checkInWindowLayout), window.ClosesAt.In(loc).Format(checkInWindowLayout))
+	}
+	return text
+}
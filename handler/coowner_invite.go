@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// How long a coowner invite link can be accepted for
+const coownerInviteLifetime = 7 * 24 * time.Hour
+
+func (o *OrganiserBotHandler) handleInvitingCoowner(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 1 && len(parts) != 2 {
+		return "Invalid format. Please use: EVENT_REF_CODE [ROLE]\nExample: ABC123 door"
+	}
+	eventID := parts[0]
+
+	// Coowners are admins unless another role is given
+	role := model.RoleAdmin
+	if len(parts) == 2 {
+		var ok bool
+		role, ok = model.ParseCoownerRole(strings.ToLower(parts[1]))
+		if !ok {
+			return "Unknown role. Please choose one of:\n" + describeCoownerRoles()
+		}
+	}
+
+	organiserID := update.Message.From.ID
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, organiserID, model.PermissionManageTeam)
+	if err != nil {
+		log.Println("error checking event permissions:", err)
+		return fmt.Sprintf("Error checking permissions for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to add coowners to this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	me, err := b.GetMe(ctx)
+	if err != nil {
+		log.Println("error getting bot details:", err)
+		return "Error creating the invite link. Please try again."
+	}
+
+	now := time.Now()
+	token, err := o.FirebaseConnector.CreateCoownerInvite(ctx, model.CoownerInvite{
+		EventID:   eventID,
+		Role:      role,
+		CreatedBy: organiserID,
+		CreatedAt: now.UTC(),
+		ExpiresAt: now.Add(coownerInviteLifetime).UTC(),
+	})
+	if err != nil {
+		log.Println("error creating coowner invite:", err)
+		return "Error creating the invite link. Please try again."
+	}
+
+	// The inviter's name is shown to the invitee and in /listCoowners
+	err = o.FirebaseConnector.UpdateOrganiserProfile(ctx, organiserID, update.Message.From.FirstName, update.Message.From.Username)
+	if err != nil {
+		log.Println("error updating organiser profile:", err)
+	}
+
+	return fmt.Sprintf("Send this link to the person you want to add to '%s' as %s (%s):\nhttps://t.me/%s?start=coown_%s\n\nIt works once and expires on %s.",
+		event.Name, role, coownerRoleDescriptions[role], me.Username, token,
+		now.Add(coownerInviteLifetime).In(o.organiserLocation(ctx, organiserID)).Format(blastTimeLayout))
+}
+
+// handleAcceptCoownerInvite adds the user who opened a coowner invite link to the event and tells the inviter and owner
+func (o *OrganiserBotHandler) handleAcceptCoownerInvite(ctx context.Context, b *bot.Bot, update *models.Update, token string) string {
+	user := update.Message.From
+
+	invite, err := o.FirebaseConnector.AcceptCoownerInvite(ctx, token, user.ID, time.Now())
+	switch {
+	case errors.Is(err, model.ErrInviteDoesNotExist):
+		return "This coowner invite link is not valid. Please ask the organiser for a new one."
+	case errors.Is(err, model.ErrInviteUsedUp):
+		return "This coowner invite link has already been used. Please ask the organiser for a new one."
+	case errors.Is(err, model.ErrInviteExpired):
+		return "This coowner invite link has expired. Please ask the organiser for a new one."
+	case errors.Is(err, model.ErrAlreadyCoowner):
+		return "You are already on this event's team. Use /viewEvents to see it."
+	case err != nil:
+		log.Println("error accepting coowner invite:", err)
+		return "Error accepting the invite. Please try again."
+	}
+
+	err = o.FirebaseConnector.UpdateOrganiserProfile(ctx, user.ID, user.FirstName, user.Username)
+	if err != nil {
+		log.Println("error updating organiser profile:", err)
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, invite.EventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("You have joined the team of event %s as %s.", invite.EventID, invite.Role)
+	}
+
+	notice := fmt.Sprintf("👥 %s accepted your invite and is now %s on '%s'.",
+		describeTeamMember(&model.Organiser{UserID: user.ID, Name: user.FirstName, Username: user.Username}), invite.Role, event.Name)
+	notified := []int64{invite.CreatedBy}
+	if event.UserID != invite.CreatedBy {
+		notified = append(notified, event.UserID)
+	}
+	for _, organiserID := range notified {
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: organiserID,
+			Text:   notice,
+		})
+		if err != nil {
+			log.Printf("error notifying organiser %d: %v", organiserID, err)
+		}
+	}
+
+	return fmt.Sprintf("You are now %s on '%s' (Reference Code: %s). You can %s.\nSend /help to see the commands.",
+		invite.Role, event.Name, invite.EventID, coownerRoleDescriptions[invite.Role])
+}
+
+func (o *OrganiserBotHandler) handleListCoowners(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	isMember, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, update.Message.From.ID)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !isMember {
+		return "Only the event's team can see its coowners."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	text := fmt.Sprintf("Team of '%s':\n", event.Name)
+	for _, memberID := range append([]int64{event.UserID}, event.Coowners...) {
+		organiser, err := o.FirebaseConnector.ReadOrganiser(ctx, memberID)
+		if err != nil {
+			log.Println("error reading organiser:", err)
+		}
+		if organiser == nil {
+			organiser = &model.Organiser{UserID: memberID}
+		}
+		text += fmt.Sprintf("- %s – %s\n", describeTeamMember(organiser), event.RoleOf(memberID))
+	}
+	if event.PendingOwner != 0 {
+		text += fmt.Sprintf("\nOwnership transfer to User %d is awaiting their confirmation.", event.PendingOwner)
+	}
+	if len(event.Coowners) == 0 {
+		text += fmt.Sprintf("\nNo coowners yet. Invite someone with /inviteCoowner %s [Role].", eventID)
+	}
+	return text
+}
+
+// describeTeamMember renders an organiser by name and username when known, always with their user ID
+func describeTeamMember(organiser *model.Organiser) string {
+	if organiser.Name == "" {
+		return fmt.Sprintf("User %d", organiser.UserID)
+	}
+	text := organiser.Name
+	if organiser.Username != "" {
+		text += " (@" + organiser.Username + ")"
+	}
+	return text + fmt.Sprintf(" [ID: %d]", organiser.UserID)
+}
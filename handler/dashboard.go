@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Dashboards also refresh on this interval to catch changes made outside this process
+	dashboardRefreshInterval = 30 * time.Second
+
+	// Minimum gap between edits so a burst of arrivals doesn't hit Telegram's edit limits
+	dashboardMinEditGap = 3 * time.Second
+
+	dashboardRecentArrivals = 5
+	dashboardMaxNotArrived  = 50
+)
+
+// checkInDashboard is a live dashboard message being kept up to date
+type checkInDashboard struct {
+	stop context.CancelFunc
+}
+
+var (
+	// Live dashboards being kept up to date, keyed by the organiser's chat ID
+	checkInDashboards   = make(map[int64]*checkInDashboard)
+	checkInDashboardsMu sync.Mutex
+
+	// Dashboards waiting to hear about check-ins, keyed by event ID
+	checkInListeners   = make(map[string]map[chan struct{}]bool)
+	checkInListenersMu sync.Mutex
+)
+
+// notifyCheckIn tells any live dashboards for the event that its check-ins changed
+func notifyCheckIn(eventID string) {
+	checkInListenersMu.Lock()
+	defer checkInListenersMu.Unlock()
+
+	for listener := range checkInListeners[eventID] {
+		select {
+		case listener <- struct{}{}:
+		default:
+			// A refresh is already pending
+		}
+	}
+}
+
+func listenForCheckIns(eventID string) chan struct{} {
+	listener := make(chan struct{}, 1)
+	checkInListenersMu.Lock()
+	defer checkInListenersMu.Unlock()
+
+	if checkInListeners[eventID] == nil {
+		checkInListeners[eventID] = make(map[chan struct{}]bool)
+	}
+	checkInListeners[eventID][listener] = true
+	return listener
+}
+
+func stopListeningForCheckIns(eventID string, listener chan struct{}) {
+	checkInListenersMu.Lock()
+	defer checkInListenersMu.Unlock()
+
+	delete(checkInListeners[eventID], listener)
+	if len(checkInListeners[eventID]) == 0 {
+		delete(checkInListeners, eventID)
+	}
+}
+
+func (o *OrganiserBotHandler) handleCheckInDashboard(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionCheckIn)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view the check-in dashboard for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	// The dashboard runs until the last check-in window closes
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	endsAt := checkInClosesAt(event, locationForOrganiser(ctx, o.FirebaseConnector, event.UserID))
+	if !endsAt.After(time.Now()) {
+		return fmt.Sprintf("Check-in for '%s' has closed. Use /listParticipants %s to see who attended.", event.Name, event.ID)
+	}
+
+	body, errText := o.checkInDashboardBody(ctx, event, loc)
+	if errText != "" {
+		return errText
+	}
+
+	chatID := update.Message.Chat.ID
+	msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   withDashboardTimestamp(body, loc),
+	})
+	if err != nil {
+		log.Println("error sending check-in dashboard:", err)
+		return "Error posting the check-in dashboard. Please try again."
+	}
+
+	// Replace any dashboard already running in this chat
+	dashboardCtx, cancel := context.WithDeadline(ctx, endsAt)
+	dashboard := &checkInDashboard{stop: cancel}
+	checkInDashboardsMu.Lock()
+	if running, ok := checkInDashboards[chatID]; ok {
+		running.stop()
+	}
+	checkInDashboards[chatID] = dashboard
+	checkInDashboardsMu.Unlock()
+
+	go o.keepCheckInDashboardLive(dashboardCtx, b, chatID, msg.ID, dashboard, event, loc, body)
+	return "The dashboard above updates as people check in. Send /stopCheckInDashboard to stop it."
+}
+
+// keepCheckInDashboardLive edits the dashboard whenever check-ins change until ctx ends
+func (o *OrganiserBotHandler) keepCheckInDashboardLive(ctx context.Context, b *bot.Bot, chatID int64, messageID int, dashboard *checkInDashboard, event *model.Event, loc *time.Location, body string) {
+	// Forget the dashboard once it ends, unless another one has replaced it
+	defer func() {
+		dashboard.stop()
+		checkInDashboardsMu.Lock()
+		if checkInDashboards[chatID] == dashboard {
+			delete(checkInDashboards, chatID)
+		}
+		checkInDashboardsMu.Unlock()
+	}()
+
+	listener := listenForCheckIns(event.ID)
+	defer stopListeningForCheckIns(event.ID, listener)
+
+	ticker := time.NewTicker(dashboardRefreshInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		_, err := b.EditMessageText(context.Background(), &bot.EditMessageTextParams{
+			ChatID:    chatID,
+			MessageID: messageID,
+			Text:      body + fmt.Sprintf("\n\nDashboard stopped at %s.", time.Now().In(loc).Format("15:04")),
+		})
+		if err != nil {
+			log.Println("error editing check-in dashboard:", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-listener:
+		case <-ticker.C:
+		}
+
+		next, errText := o.checkInDashboardBody(ctx, event, loc)
+		if errText != "" || next == body {
+			continue
+		}
+		body = next
+
+		_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    chatID,
+			MessageID: messageID,
+			Text:      withDashboardTimestamp(body, loc),
+		})
+		if err != nil {
+			log.Println("error editing check-in dashboard:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dashboardMinEditGap):
+		}
+	}
+}
+
+// checkInDashboardBody reads the event's participants and renders the dashboard; errText is set on failure
+func (o *OrganiserBotHandler) checkInDashboardBody(ctx context.Context, event *model.Event, loc *time.Location) (string, string) {
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", event.ID, err)
+		return "", "Error retrieving participants. Please try again."
+	}
+	return buildCheckInDashboard(event, participants, loc), ""
+}
+
+// buildCheckInDashboard summarises who has arrived: totals, the latest arrivals and who is still missing
+func buildCheckInDashboard(event *model.Event, participants []model.Participant, loc *time.Location) string {
+	type arrival struct {
+		name string
+		at   time.Time
+	}
+
+	var arrived []arrival
+	var notArrived []string
+	guests := 0
+	for i := range participants {
+		signedUpEvent := findSignedUpEvent(&participants[i], event.ID)
+		if signedUpEvent == nil {
+			continue
+		}
+		if signedUpEvent.CheckedIn {
+			arrived = append(arrived, arrival{name: participants[i].Name, at: signedUpEvent.CheckedInAt})
+		} else {
+			notArrived = append(notArrived, participants[i].Name)
+		}
+
+		// Guests are listed under the participant who registered them
+		for _, guest := range signedUpEvent.Guests {
+			name := fmt.Sprintf("%s (guest of %s)", guest.Name, participants[i].Name)
+			if guest.CheckedIn {
+				arrived = append(arrived, arrival{name: name, at: guest.CheckedInAt})
+			} else {
+				notArrived = append(notArrived, name)
+			}
+		}
+		guests += len(signedUpEvent.Guests)
+	}
+	total := len(arrived) + len(notArrived)
+
+	text := fmt.Sprintf("📋 Check-in dashboard: %s\n", event.Name)
+	if guests > 0 {
+		text += fmt.Sprintf("Registered: %d (%d participants, %d guests)\n", total, total-guests, guests)
+	} else {
+		text += fmt.Sprintf("Registered: %d\n", total)
+	}
+	if event.Capacity > 0 {
+		text += fmt.Sprintf("Capacity: %d\n", event.Capacity)
+	}
+	text += formatBarLine("checked in", len(arrived), total)
+
+	sort.Slice(arrived, func(i, j int) bool {
+		return arrived[i].at.After(arrived[j].at)
+	})
+	if len(arrived) > 0 {
+		text += "\nRecent arrivals:\n"
+		for i, a := range arrived {
+			if i == dashboardRecentArrivals {
+				break
+			}
+			if a.at.IsZero() {
+				text += fmt.Sprintf("- %s\n", a.name)
+			} else {
+				text += fmt.Sprintf("- %s %s\n", a.at.In(loc).Format("15:04"), a.name)
+			}
+		}
+	}
+
+	sort.Strings(notArrived)
+	if len(notArrived) > 0 {
+		text += fmt.Sprintf("\nNot yet arrived (%d):\n", len(notArrived))
+		for i, name := range notArrived {
+			if i == dashboardMaxNotArrived {
+				text += fmt.Sprintf("…and %d more\n", len(notArrived)-dashboardMaxNotArrived)
+				break
+			}
+			text += fmt.Sprintf("- %s\n", name)
+		}
+	}
+	return text
+}
+
+func withDashboardTimestamp(body string, loc *time.Location) string {
+	return body + fmt.Sprintf("\nUpdated %s", time.Now().In(loc).Format("15:04:05"))
+}
+
+// stopCheckInDashboard stops the dashboard in a chat, reporting whether one was running
+func stopCheckInDashboard(chatID int64) bool {
+	checkInDashboardsMu.Lock()
+	defer checkInDashboardsMu.Unlock()
+
+	dashboard, ok := checkInDashboards[chatID]
+	if ok {
+		dashboard.stop()
+		delete(checkInDashboards, chatID)
+	}
+	return ok
+}
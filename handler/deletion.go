@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// How long a deleted event can still be restored before it is removed for good
+	eventRestoreWindow = 72 * time.Hour
+
+	// How often deleted events are checked for a closed restore window
+	eventPurgeInterval = 10 * time.Minute
+)
+
+func (o *OrganiserBotHandler) handleDeleteEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionDeleteEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "Only the event owner can delete an event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+	if event.IsDeleted() {
+		return fmt.Sprintf("'%s' has already been deleted. Use /restoreEvent %s to bring it back.", event.Name, eventID)
+	}
+
+	userState.CurrentEvent = event
+	userState.State = model.StateConfirmDeleteEvent
+	return fmt.Sprintf("Delete '%s' on %s? It has %d participants.\n"+
+		"You can restore it with /restoreEvent for %d hours, after which it is removed from everyone's events.\n"+
+		"1. Delete and tell participants once it is removed\n"+
+		"2. Delete without telling participants\n"+
+		"3. Keep the event",
+		event.Name, event.EventDate.Format("2006-01-02"), len(event.Participants)+len(event.Applicants), int(eventRestoreWindow.Hours()))
+}
+
+func (o *OrganiserBotHandler) handleConfirmDeleteEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	var notify bool
+	switch strings.TrimSpace(update.Message.Text) {
+	case "1":
+		notify = true
+	case "2":
+	case "3", "Cancel":
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return "The event has not been deleted."
+	default:
+		return "Invalid option. Please choose 1-3."
+	}
+
+	event := userState.CurrentEvent
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+
+	organiserID := update.Message.From.ID
+	now := time.Now()
+	event, err := o.FirebaseConnector.SoftDeleteEvent(ctx, event.ID, organiserID, now, now.Add(eventRestoreWindow), notify)
+	if errors.Is(err, model.ErrEventDeleted) {
+		return "The event has already been deleted."
+	} else if err != nil {
+		log.Println("error deleting event:", err)
+		return "Error deleting the event. Please try again."
+	}
+	notifyCheckIn(event.ID)
+
+	text := fmt.Sprintf("'%s' has been deleted. Participants can no longer see, join or check in to it.\n"+
+		"Changed your mind? Send /restoreEvent %s before %s.",
+		event.Name, event.ID, event.PurgeAt.In(o.organiserLocation(ctx, organiserID)).Format(blastTimeLayout))
+	if notify {
+		text += "\nParticipants will be told once the event is removed."
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleRestoringEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionDeleteEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. It may already have been removed for good.", eventID)
+	}
+	if !allowed {
+		return "Only the event owner can restore an event."
+	}
+
+	event, err := o.FirebaseConnector.RestoreEvent(ctx, eventID, time.Now())
+	switch {
+	case errors.Is(err, model.ErrEventNotDeleted):
+		return "This event has not been deleted."
+	case errors.Is(err, model.ErrRestoreWindowClosed):
+		return "The restore window for this event has closed."
+	case err != nil:
+		log.Println("error restoring event:", err)
+		return "Error restoring the event. Please try again."
+	}
+	notifyCheckIn(eventID)
+
+	return fmt.Sprintf("'%s' has been restored. Participants can see it again.", event.Name)
+}
+
+// RunEventPurger periodically removes deleted events whose restore window has closed
+func (o *OrganiserBotHandler) RunEventPurger(ctx context.Context, b *bot.Bot) {
+	ticker := time.NewTicker(eventPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		o.purgeDeletedEvents(ctx, b)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *OrganiserBotHandler) purgeDeletedEvents(ctx context.Context, b *bot.Bot) {
+	events, err := o.FirebaseConnector.ListEventsDueForPurge(ctx, time.Now())
+	if err != nil {
+		log.Println("error listing deleted events:", err)
+		return
+	}
+
+	for _, event := range events {
+		removed, err := o.FirebaseConnector.PurgeEvent(ctx, event.ID, time.Now())
+		if errors.Is(err, model.ErrEventNotDeleted) {
+			// Restored since it was listed
+			continue
+		} else if err != nil {
+			log.Printf("error purging event %s: %v", event.ID, err)
+			continue
+		}
+
+		notified := 0
+		if event.NotifyOnPurge && len(removed) > 0 {
+			notified = o.sendDeletionNotice(ctx, &event, removed)
+		}
+
+		summary := fmt.Sprintf("'%s' has now been removed for good and taken off %d participants' events.", event.Name, len(removed))
+		if event.NotifyOnPurge {
+			summary += fmt.Sprintf(" %d of them have been told.", notified)
+		}
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: event.DeletedBy,
+			Text:   summary,
+		})
+		if err != nil {
+			log.Println("error sending purge summary:", err)
+		}
+	}
+}
+
+// sendDeletionNotice tells participants that the event they joined has been removed, returning how many were reached
+func (o *OrganiserBotHandler) sendDeletionNotice(ctx context.Context, event *model.Event, participants []model.Participant) int {
+	participantBot, err := newParticipantBot()
+	if err != nil {
+		log.Println("error creating participant bot:", err)
+		return 0
+	}
+
+	notice := fmt.Sprintf("'%s' on %s has been removed by the organiser and is no longer one of your events.",
+		event.Name, event.EventDate.Format("2006-01-02"))
+	notified := 0
+	for _, participant := range participants {
+		_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: participant.UserID,
+			Text:   notice,
+		})
+		if err != nil {
+			log.Printf("error notifying participant %d of deletion: %v", participant.UserID, err)
+			continue
+		}
+		notified++
+	}
+	return notified
+}
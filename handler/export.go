@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"EventBot/model"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// Layout used for timestamps in exported files
+const exportTimeLayout = "2006-01-02 15:04"
+
+func (o *OrganiserBotHandler) handleExportParticipants(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	eventID := update.Message.Text
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		userState.State = model.StateIdle
+		return "You don't have permission to export participants of this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	// Only ask about the layout when there is a multi-select question to expand
+	hasMultiSelect := slices.ContainsFunc(event.RSVPQuestions, func(q model.RSVPQuestion) bool {
+		return q.Type == model.QuestionTypeMultiSelect
+	})
+	if !hasMultiSelect {
+		userState.State = model.StateIdle
+		return o.sendParticipantsCSV(ctx, b, update, event, false)
+	}
+
+	userState.CurrentEvent = event
+	userState.State = model.StateSelectExportFormat
+	return "How should multi-select answers be exported?\n" +
+		"1. Joined into one column per question\n" +
+		"2. Expanded into one column per option"
+}
+
+func (o *OrganiserBotHandler) handleSelectExportFormat(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	var expand bool
+	switch update.Message.Text {
+	case "1":
+		expand = false
+	case "2":
+		expand = true
+	default:
+		return "Invalid option. Please choose 1 or 2."
+	}
+
+	event := userState.CurrentEvent
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	return o.sendParticipantsCSV(ctx, b, update, event, expand)
+}
+
+// sendParticipantsCSV builds the participant export for an event and sends it as a document
+func (o *OrganiserBotHandler) sendParticipantsCSV(ctx context.Context, b *bot.Bot, update *models.Update, event *model.Event, expandMultiSelect bool) string {
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", event.ID, err)
+		return "Error retrieving participants. Please try again."
+	}
+	if len(participants) == 0 {
+		return fmt.Sprintf("No participants found for event '%s'.", event.Name)
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	data, err := buildParticipantsCSV(event, participants, expandMultiSelect, loc)
+	if err != nil {
+		log.Println("error building participants CSV:", err)
+		return "Error generating the export. Please try again."
+	}
+
+	_, err = b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:   update.Message.Chat.ID,
+		Document: &models.InputFileUpload{Filename: exportFilename(event, "participants"), Data: bytes.NewReader(data)},
+		Caption:  fmt.Sprintf("%d participants of '%s'%s (times in %s)", len(participants), event.Name, describeExportGuests(event, participants), loc),
+	})
+	if err != nil {
+		log.Println("error sending document:", err)
+		return "Error sending the export. Please try again."
+	}
+	return ""
+}
+
+// describeExportGuests counts the participants' guests for the export caption, or returns "" if there are none
+func describeExportGuests(event *model.Event, participants []model.Participant) string {
+	guests := 0
+	for i := range participants {
+		if signedUpEvent := findSignedUpEvent(&participants[i], event.ID); signedUpEvent != nil {
+			guests += len(signedUpEvent.Guests)
+		}
+	}
+	if guests == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" with %d guests", guests)
+}
+
+// buildParticipantsCSV writes one row per participant with their sign-up, check-in and RSVP data, followed by a row per guest
+func buildParticipantsCSV(event *model.Event, participants []model.Participant, expandMultiSelect bool, loc *time.Location) ([]byte, error) {
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].Name < participants[j].Name
+	})
+
+	header := []string{"Name", "Telegram User ID", "Username", "Guest Of", "Joined At", "Invite", "Checked In", "Checked In At", "Checked In By"}
+	for _, question := range event.RSVPQuestions {
+		if expandMultiSelect && question.Type == model.QuestionTypeMultiSelect {
+			for _, option := range question.Options {
+				header = append(header, fmt.Sprintf("%s: %s", question.Question, option))
+			}
+			header = append(header, fmt.Sprintf("%s: Other", question.Question))
+			continue
+		}
+		header = append(header, question.Question)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for i := range participants {
+		participant := &participants[i]
+		signedUpEvent := findSignedUpEvent(participant, event.ID)
+		if signedUpEvent == nil {
+			signedUpEvent = &model.SignedUpEvent{EventID: event.ID}
+		}
+
+		username := ""
+		if participant.Username != "" {
+			username = "@" + participant.Username
+		}
+
+		row := []string{
+			participant.Name,
+			strconv.FormatInt(participant.UserID, 10),
+			username,
+			"",
+			formatExportTime(signedUpEvent.JoinedAt, loc),
+			formatInviteUsed(signedUpEvent),
+			formatYesNo(signedUpEvent.CheckedIn),
+			formatExportTime(signedUpEvent.CheckedInAt, loc),
+			formatCheckedInBy(participant, signedUpEvent),
+		}
+		row = appendRSVPColumns(row, event, signedUpEvent.RSVPAnswers, expandMultiSelect)
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+
+		for _, guest := range signedUpEvent.Guests {
+			guestBy := ""
+			if guest.CheckedInBy != 0 {
+				guestBy = strconv.FormatInt(guest.CheckedInBy, 10)
+			}
+			row := []string{
+				guest.Name,
+				"",
+				"",
+				participant.Name,
+				formatExportTime(signedUpEvent.JoinedAt, loc),
+				formatInviteUsed(signedUpEvent),
+				formatYesNo(guest.CheckedIn),
+				formatExportTime(guest.CheckedInAt, loc),
+				guestBy,
+			}
+			row = appendRSVPColumns(row, event, guest.RSVPAnswers, expandMultiSelect)
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// appendRSVPColumns adds the answers to each RSVP question to an export row, splitting multi-select questions per option if asked to
+func appendRSVPColumns(row []string, event *model.Event, rsvpAnswers []model.RSVPAnswer, expandMultiSelect bool) []string {
+	signedUpEvent := &model.SignedUpEvent{RSVPAnswers: rsvpAnswers}
+	for _, question := range event.RSVPQuestions {
+		answers := findRSVPAnswers(signedUpEvent, question.ID)
+		if expandMultiSelect && question.Type == model.QuestionTypeMultiSelect {
+			var other []string
+			for _, answer := range answers {
+				if !slices.Contains(question.Options, answer) {
+					other = append(other, answer)
+				}
+			}
+			for _, option := range question.Options {
+				row = append(row, formatYesNo(slices.Contains(answers, option)))
+			}
+			row = append(row, strings.Join(other, "; "))
+			continue
+		}
+		row = append(row, strings.Join(answers, "; "))
+	}
+	return row
+}
+
+// findSignedUpEvent returns the participant's sign-up for the event, or nil if they are not signed up
+func findSignedUpEvent(participant *model.Participant, eventID string) *model.SignedUpEvent {
+	for i := range participant.SignedUpEvents {
+		if participant.SignedUpEvents[i].EventID == eventID {
+			return &participant.SignedUpEvents[i]
+		}
+	}
+	return nil
+}
+
+// findRSVPAnswers returns the answers given to an RSVP question, or nil if it was not answered
+func findRSVPAnswers(signedUpEvent *model.SignedUpEvent, questionID string) []string {
+	for _, rsvpAnswer := range signedUpEvent.RSVPAnswers {
+		if rsvpAnswer.QuestionID == questionID {
+			return rsvpAnswer.Answers
+		}
+	}
+	return nil
+}
+
+func formatExportTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(exportTimeLayout)
+}
+
+// formatCheckedInBy shows who checked a participant in: "Self", or the organiser's user ID
+func formatCheckedInBy(participant *model.Participant, signedUpEvent *model.SignedUpEvent) string {
+	switch signedUpEvent.CheckedInBy {
+	case 0:
+		return ""
+	case participant.UserID:
+		return "Self"
+	default:
+		return strconv.FormatInt(signedUpEvent.CheckedInBy, 10)
+	}
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// exportFilename builds a filesystem-friendly document name for an event export
+func exportFilename(event *model.Event, kind string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, event.Name)
+	return fmt.Sprintf("%s_%s_%s.csv", name, kind, time.Now().Format("20060102"))
+}
@@ -0,0 +1,606 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Upper bound for the per-event guest allowance
+	maxGuestsPerParticipant = 10
+
+	// Guest names longer than this are cut short
+	maxGuestNameLength = 64
+)
+
+// Matches the guest selection at the end of /checkInGuests: "all" or guest numbers such as "1,3"
+var guestSelectionPattern = regexp.MustCompile(`(?i)^(all|\d+(,\d+)*)$`)
+
+// describeParty renders the participant's guests with their check-in status, numbered for /checkInGuests
+func describeParty(signedUpEvent *model.SignedUpEvent) string {
+	text := ""
+	for i, guest := range signedUpEvent.Guests {
+		status := "not checked in"
+		if guest.CheckedIn {
+			status = "checked in"
+		}
+		text += fmt.Sprintf("%d. %s – %s\n", i+1, guest.Name, status)
+	}
+	return text
+}
+
+// describeGuestCount summarises a participant's guests on one line, or "" if they have none
+func describeGuestCount(signedUpEvent *model.SignedUpEvent) string {
+	if signedUpEvent == nil || len(signedUpEvent.Guests) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" +%d guests (%d checked in)", len(signedUpEvent.Guests), signedUpEvent.GuestsCheckedIn())
+}
+
+// guestCheckInHint reminds door staff of the participant's guests who haven't arrived yet, or returns "" if there are none
+func guestCheckInHint(event *model.Event, participant *model.Participant) string {
+	signedUpEvent := findSignedUpEvent(participant, event.ID)
+	if signedUpEvent == nil {
+		return ""
+	}
+
+	var names []string
+	for _, guest := range signedUpEvent.Guests {
+		if !guest.CheckedIn {
+			names = append(names, guest.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n👥 Guests not checked in yet: %s\nCheck them all in with /checkInGuests %s %d all, or see the party with /checkInGuests %s %d.",
+		strings.Join(names, ", "), event.ID, participant.UserID, event.ID, participant.UserID)
+}
+
+func (o *OrganiserBotHandler) handleSettingGuestPolicy(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && !strings.EqualFold(parts[2], "rsvp")) {
+		return "Invalid format. Please use: EVENT_REF_CODE MAX_GUESTS [rsvp]\nExample: ABC123 2 rsvp"
+	}
+	maxGuests, err := strconv.Atoi(parts[1])
+	if err != nil || maxGuests < 0 || maxGuests > maxGuestsPerParticipant {
+		return fmt.Sprintf("Please give a number of guests between 0 and %d.", maxGuestsPerParticipant)
+	}
+	guestRSVP := len(parts) == 3
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, parts[0], update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", parts[0])
+	}
+	if !allowed {
+		return "You don't have permission to change the guest settings of this event."
+	}
+
+	event, err := o.FirebaseConnector.SetGuestPolicy(ctx, parts[0], maxGuests, guestRSVP)
+	if err != nil {
+		log.Println("error setting guest policy:", err)
+		return "Error saving the guest settings. Please try again."
+	}
+
+	if maxGuests == 0 {
+		return fmt.Sprintf("Participants of '%s' can no longer bring guests. Guests registered already keep their place.", event.Name)
+	}
+	text := fmt.Sprintf("Participants of '%s' can now bring up to %d guests each. They'll be asked for their guests' names when they join, or can add them with /guests.", event.Name, maxGuests)
+	if guestRSVP && len(event.RSVPQuestions) > 0 {
+		text += "\nGuests will answer the RSVP questions too."
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleSettingCapacity(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return "Invalid format. Please use: EVENT_REF_CODE CAPACITY, or EVENT_REF_CODE off\nExample: ABC123 120"
+	}
+	capacity := 0
+	if !strings.EqualFold(parts[1], "off") {
+		var err error
+		capacity, err = strconv.Atoi(parts[1])
+		if err != nil || capacity < 1 {
+			return "Please give the capacity as a positive number, or 'off' to remove the limit."
+		}
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, parts[0], update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", parts[0])
+	}
+	if !allowed {
+		return "You don't have permission to change the capacity of this event."
+	}
+
+	event, err := o.FirebaseConnector.SetCapacity(ctx, parts[0], capacity)
+	if err != nil {
+		log.Println("error setting capacity:", err)
+		return "Error saving the capacity. Please try again."
+	}
+
+	if capacity == 0 {
+		return fmt.Sprintf("'%s' no longer has a capacity limit. %d people are registered, guests included.", event.Name, event.Headcount())
+	}
+	text := fmt.Sprintf("'%s' can now take %d people, guests included. %d are registered.", event.Name, capacity, event.Headcount())
+	if event.Headcount() > capacity {
+		text += " The event is already over capacity, so no one else can join until places free up."
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleCheckInGuests(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.applyGuestCheckIn(ctx, update, userState, false)
+}
+
+func (o *OrganiserBotHandler) handleUndoGuestCheckIn(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.applyGuestCheckIn(ctx, update, userState, true)
+}
+
+// applyGuestCheckIn handles "EVENT_REF_CODE NAME_OR_USER_ID [all|1,3]": without a selection it shows the party,
+// with "all" it checks in the whole party and with guest numbers only those guests
+func (o *OrganiserBotHandler) applyGuestCheckIn(ctx context.Context, update *models.Update, userState *model.UserState, undo bool) string {
+	userState.State = model.StateIdle
+	command := "/checkInGuests"
+	if undo {
+		command = "/undoGuestCheckIn"
+	}
+
+	eventID, rest, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "Invalid format. Please use: EVENT_REF_CODE NAME_OR_USER_ID [all|GUEST_NUMBERS]\nExample: ABC123 Alice all"
+	}
+	selection := ""
+	if len(fields) > 1 && guestSelectionPattern.MatchString(fields[len(fields)-1]) {
+		selection = strings.ToLower(fields[len(fields)-1])
+		fields = fields[:len(fields)-1]
+	}
+	query := strings.Join(fields, " ")
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionCheckIn)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to check guests in for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+	if blockedText := checkInBlockedText(event); blockedText != "" && !undo {
+		return blockedText
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+	matches := matchParticipants(participants, query)
+	switch {
+	case len(matches) == 0:
+		return fmt.Sprintf("No participant of '%s' matches '%s'.", event.Name, query)
+	case len(matches) > 1:
+		text := fmt.Sprintf("Several participants match '%s'. Please send the command again with one of their User IDs:\n", query)
+		for i, participant := range matches {
+			if i == maxManualCheckInMatches {
+				break
+			}
+			text += fmt.Sprintf("- %s [ID: %d]\n", participant.Name, participant.UserID)
+		}
+		return text
+	}
+	participant := &matches[0]
+
+	signedUpEvent := findSignedUpEvent(participant, eventID)
+	if signedUpEvent == nil || len(signedUpEvent.Guests) == 0 {
+		return fmt.Sprintf("%s has no guests registered for '%s'.", participant.Name, event.Name)
+	}
+
+	if selection == "" {
+		loc := o.organiserLocation(ctx, update.Message.From.ID)
+		return fmt.Sprintf("Party of %s:\n%s\n%s\n\nSend %s %s %d all for the whole party, or guest numbers such as %s %s %d 1,2.",
+			participant.Name, describeCheckInStatus(participant, eventID, loc), describeParty(signedUpEvent),
+			command, eventID, participant.UserID, command, eventID, participant.UserID)
+	}
+
+	var indexes []int
+	if selection == "all" {
+		for i := range signedUpEvent.Guests {
+			indexes = append(indexes, i)
+		}
+	} else {
+		for _, number := range strings.Split(selection, ",") {
+			n, _ := strconv.Atoi(number)
+			if n < 1 || n > len(signedUpEvent.Guests) {
+				return fmt.Sprintf("%s has %d guests. Please use guest numbers between 1 and %d.", participant.Name, len(signedUpEvent.Guests), len(signedUpEvent.Guests))
+			}
+			indexes = append(indexes, n-1)
+		}
+	}
+
+	// The whole party includes the registrant themselves
+	text := ""
+	if selection == "all" && !undo && !signedUpEvent.CheckedIn {
+		// The guests are checked in below, so there's no need to point to them
+		text = o.applyManualCheckIn(ctx, event, participant, update.Message.From.ID, false, false) + "\n"
+	}
+
+	now := time.Now()
+	updated, changed, err := o.FirebaseConnector.SetGuestCheckIn(ctx, participant.ID, eventID, indexes, !undo, now, update.Message.From.ID)
+	switch {
+	case errors.Is(err, model.ErrApplicationPending):
+		return fmt.Sprintf("❌ %s's application hasn't been approved yet.", participant.Name)
+	case errors.Is(err, model.ErrNotSignedUp):
+		return fmt.Sprintf("%s is not registered for '%s'.", participant.Name, event.Name)
+	case err != nil:
+		log.Println("error checking in guests:", err)
+		return text + "Error updating the guests' check-in. Please try again."
+	}
+
+	var names []string
+	for _, index := range indexes {
+		names = append(names, signedUpEvent.Guests[index].Name)
+	}
+	if changed > 0 {
+		notifyCheckIn(eventID)
+
+		action := model.AuditActionManualCheckIn
+		if undo {
+			action = model.AuditActionCheckInUndone
+		}
+		err = o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+			EventID:      eventID,
+			Action:       action,
+			ActorID:      update.Message.From.ID,
+			TargetUserID: participant.UserID,
+			Details:      "guests: " + strings.Join(names, ", "),
+			At:           now.UTC(),
+		})
+		if err != nil {
+			log.Println("error recording audit entry:", err)
+		}
+	}
+
+	if undo {
+		text += fmt.Sprintf("↩️ Check-in undone for %d of %s's guests.", changed, participant.Name)
+	} else {
+		text += fmt.Sprintf("✅ %d guests of %s checked in.", changed, participant.Name)
+	}
+	if changed < len(indexes) {
+		text += fmt.Sprintf(" %d were already in that state.", len(indexes)-changed)
+	}
+	return text + "\n\n" + describeParty(findSignedUpEvent(updated, eventID))
+}
+
+// guestsToOffer returns how many guests the participant may still register, limited by the places left
+func guestsToOffer(event *model.Event) int {
+	allowance := event.MaxGuests
+	if places := event.PlacesLeft(); places >= 0 && places < allowance {
+		allowance = places
+	}
+	return allowance
+}
+
+// finishRegistration follows a completed sign-up with the guest and series offers, then the main menu
+func (p *ParticipantBotHandler) finishRegistration(ctx context.Context, userState *model.UserState, event *model.Event) {
+	if p.offerGuests(ctx, userState, event) {
+		return
+	}
+	p.finishSignUp(ctx, userState, event)
+}
+
+// finishSignUp offers the rest of a series, or shows the main menu
+func (p *ParticipantBotHandler) finishSignUp(ctx context.Context, userState *model.UserState, event *model.Event) {
+	// Sessions of a series can be joined together
+	if p.offerSeriesJoin(ctx, userState, event) {
+		return
+	}
+
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        "What would you like to do next?",
+		ReplyMarkup: getParticipantMainMenuKeyboard(),
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// offerGuests asks a participant who just signed up whether they bring guests, returning false if the event takes none
+func (p *ParticipantBotHandler) offerGuests(ctx context.Context, userState *model.UserState, event *model.Event) bool {
+	if event == nil || event.MaxGuests == 0 {
+		return false
+	}
+
+	// Read the event again so the places left include everyone who joined meanwhile
+	latest, err := p.FirebaseConnector.ReadEvent(ctx, event.ID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return false
+	}
+	latest.ID = event.ID
+	allowance := guestsToOffer(latest)
+	if allowance == 0 {
+		return false
+	}
+
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		return false
+	}
+	if signedUpEvent := findSignedUpEvent(participant, event.ID); signedUpEvent == nil || len(signedUpEvent.Guests) > 0 {
+		return false
+	}
+
+	p.promptGuestCount(ctx, userState, latest, allowance,
+		fmt.Sprintf("Are you bringing anyone who isn't on Telegram? You can register up to %d guests for '%s'.", allowance, latest.Name))
+	return true
+}
+
+// promptGuestCount asks how many guests the participant brings, offering each allowed number as a button
+func (p *ParticipantBotHandler) promptGuestCount(ctx context.Context, userState *model.UserState, event *model.Event, allowance int, intro string) {
+	var row []models.KeyboardButton
+	for n := 0; n <= allowance; n++ {
+		row = append(row, models.KeyboardButton{Text: strconv.Itoa(n)})
+	}
+
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: p.update.Message.Chat.ID,
+		Text:   intro + "\nReply with the number of guests, or 0 for none.",
+		ReplyMarkup: &models.ReplyKeyboardMarkup{
+			Keyboard:        [][]models.KeyboardButton{row},
+			ResizeKeyboard:  true,
+			OneTimeKeyboard: true,
+		},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+
+	userState.CurrentEvent = event
+	userState.GuestNames = nil
+	userState.GuestAllowance = allowance
+	userState.State = model.StateEnteringGuestCount
+}
+
+func (p *ParticipantBotHandler) handleSelectGuestsEvent(ctx context.Context, userState *model.UserState) {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(p.update.Message.Text)
+
+	text := ""
+	event, err := p.FirebaseConnector.ReadEvent(ctx, eventID)
+	participant, participantErr := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	switch {
+	case err != nil:
+		log.Println("error reading event:", err)
+		text = fmt.Sprintf("Error finding event with ID '%s'. Please check the ID and try again.", eventID)
+	case participantErr != nil || participant == nil || findSignedUpEvent(participant, eventID) == nil:
+		text = "You are not registered for this event. Please join the event first."
+	case joinBlockedText(event) != "":
+		text = fmt.Sprintf("The guests for '%s' can't be changed any more.", event.Name)
+	case event.MaxGuests == 0 && len(findSignedUpEvent(participant, eventID).Guests) == 0:
+		text = fmt.Sprintf("'%s' doesn't take guests.", event.Name)
+	}
+	if text != "" {
+		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      p.update.Message.Chat.ID,
+			Text:        text,
+			ReplyMarkup: getParticipantMainMenuKeyboard(),
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
+		}
+		return
+	}
+	event.ID = eventID
+
+	// Guests already registered keep their places when the number is asked again
+	guests := findSignedUpEvent(participant, eventID).Guests
+	allowance := guestsToOffer(event)
+	if slices.Contains(event.Participants, participant.ID) {
+		allowance = min(event.MaxGuests, allowance+len(guests))
+	}
+	allowance = max(allowance, len(guests))
+
+	intro := fmt.Sprintf("You have no guests registered for '%s'. You can bring up to %d.", event.Name, allowance)
+	if len(guests) > 0 {
+		names := make([]string, 0, len(guests))
+		for _, guest := range guests {
+			names = append(names, guest.Name)
+		}
+		intro = fmt.Sprintf("Your guests for '%s': %s.\nHow many guests are you bringing now? You can bring up to %d; you'll be asked for their names again.",
+			event.Name, strings.Join(names, ", "), allowance)
+	}
+	p.promptGuestCount(ctx, userState, event, allowance, intro)
+}
+
+func (p *ParticipantBotHandler) handleGuestCount(ctx context.Context, userState *model.UserState) {
+	count, err := strconv.Atoi(strings.TrimSpace(p.update.Message.Text))
+	if err != nil || count < 0 || count > userState.GuestAllowance {
+		p.sendGuestPrompt(ctx, fmt.Sprintf("Please reply with a number between 0 and %d.", userState.GuestAllowance))
+		return
+	}
+
+	if count == 0 {
+		p.saveGuests(ctx, userState, nil)
+		return
+	}
+
+	userState.GuestCount = count
+	userState.GuestNames = nil
+	userState.State = model.StateEnteringGuestName
+	p.sendGuestPrompt(ctx, fmt.Sprintf("What's the name of guest 1 of %d?", count))
+}
+
+func (p *ParticipantBotHandler) handleGuestName(ctx context.Context, userState *model.UserState) {
+	name := strings.TrimSpace(p.update.Message.Text)
+	if name == "" || strings.HasPrefix(name, "/") {
+		p.sendGuestPrompt(ctx, "Please send your guest's name.")
+		return
+	}
+	if runes := []rune(name); len(runes) > maxGuestNameLength {
+		name = string(runes[:maxGuestNameLength])
+	}
+
+	userState.GuestNames = append(userState.GuestNames, name)
+	if len(userState.GuestNames) < userState.GuestCount {
+		p.sendGuestPrompt(ctx, fmt.Sprintf("What's the name of guest %d of %d?", len(userState.GuestNames)+1, userState.GuestCount))
+		return
+	}
+
+	guests := make([]model.Guest, 0, len(userState.GuestNames))
+	for _, name := range userState.GuestNames {
+		guests = append(guests, model.Guest{Name: name})
+	}
+	p.saveGuests(ctx, userState, guests)
+}
+
+// saveGuests registers the participant's guests and, if the event asks for it, starts their RSVP questions
+func (p *ParticipantBotHandler) saveGuests(ctx context.Context, userState *model.UserState, guests []model.Guest) {
+	event := userState.CurrentEvent
+
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err == nil && participant != nil {
+		_, err = p.FirebaseConnector.SetGuests(ctx, event.ID, participant.ID, guests)
+	} else if err == nil {
+		err = model.ErrParticipantDoesNotExist
+	}
+
+	var text string
+	switch {
+	case errors.Is(err, model.ErrEventFull):
+		latest, readErr := p.FirebaseConnector.ReadEvent(ctx, event.ID)
+		places := 0
+		if readErr == nil {
+			places = max(latest.PlacesLeft(), 0)
+		}
+		text = fmt.Sprintf("Sorry, '%s' only has %d places left, so your guests couldn't be registered. Use /guests to try fewer.", event.Name, places)
+	case errors.Is(err, model.ErrTooManyGuests):
+		text = fmt.Sprintf("Sorry, '%s' now takes at most %d guests per participant. Use /guests to try again.", event.Name, event.MaxGuests)
+	case err != nil:
+		log.Println("error saving guests:", err)
+		text = "Error registering your guests. Please try again with /guests."
+	case len(guests) == 0:
+		text = "Okay, no guests."
+	case event.GuestRSVP && len(event.RSVPQuestions) > 0:
+		userState.GuestNames = nil
+		for _, guest := range guests {
+			userState.GuestNames = append(userState.GuestNames, guest.Name)
+		}
+		userState.GuestIndex = 0
+		userState.RSVPQuestionIndex = 0
+		userState.State = model.StateAnsweringGuestRSVP
+		p.sendGuestPrompt(ctx, fmt.Sprintf("Please answer the RSVP questions for each guest, starting with %s.", guests[0].Name))
+		p.askRSVPQuestion(ctx, userState)
+		return
+	default:
+		text = fmt.Sprintf("Your %d guests are registered. They can check in with you at the door.", len(guests))
+	}
+
+	p.endGuestFlow(ctx, userState, text)
+}
+
+func (p *ParticipantBotHandler) handleGuestRSVPAnswer(ctx context.Context, userState *model.UserState) {
+	question := userState.CurrentEvent.RSVPQuestions[userState.RSVPQuestionIndex]
+	answers, errText := parseRSVPAnswer(&question, p.update.Message.Text)
+	if errText != "" {
+		p.sendGuestPrompt(ctx, errText)
+		return
+	}
+
+	guestIndex := userState.GuestIndex
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+	} else if signedUpEvent := findSignedUpEvent(participant, userState.CurrentEvent.ID); signedUpEvent != nil && guestIndex < len(signedUpEvent.Guests) {
+		guest := &signedUpEvent.Guests[guestIndex]
+		i := slices.IndexFunc(guest.RSVPAnswers, func(a model.RSVPAnswer) bool { return a.QuestionID == question.ID })
+		if i >= 0 {
+			guest.RSVPAnswers[i].Answers = answers
+		} else {
+			guest.RSVPAnswers = append(guest.RSVPAnswers, model.RSVPAnswer{QuestionID: question.ID, Answers: answers})
+		}
+		if err := p.FirebaseConnector.UpdateParticipant(ctx, *participant); err != nil {
+			log.Println("error updating participant:", err)
+		}
+	}
+
+	userState.RSVPQuestionIndex++
+	p.askRSVPQuestion(ctx, userState)
+}
+
+// nextGuestRSVP moves on to the next guest's RSVP questions once one guest has answered them all
+func (p *ParticipantBotHandler) nextGuestRSVP(ctx context.Context, userState *model.UserState) {
+	userState.GuestIndex++
+	if userState.GuestIndex >= len(userState.GuestNames) {
+		p.endGuestFlow(ctx, userState, fmt.Sprintf("Thank you! Your %d guests are registered with their RSVP answers.", len(userState.GuestNames)))
+		return
+	}
+
+	userState.RSVPQuestionIndex = 0
+	p.sendGuestPrompt(ctx, fmt.Sprintf("Now for %s.", userState.GuestNames[userState.GuestIndex]))
+	p.askRSVPQuestion(ctx, userState)
+}
+
+// currentGuestName is the guest whose RSVP questions are being asked, or "" when the participant answers for themselves
+func currentGuestName(userState *model.UserState) string {
+	if userState.State != model.StateAnsweringGuestRSVP {
+		return ""
+	}
+	if userState.GuestIndex >= len(userState.GuestNames) {
+		return ""
+	}
+	return userState.GuestNames[userState.GuestIndex]
+}
+
+// endGuestFlow reports the outcome of registering guests and carries on with the rest of the sign-up
+func (p *ParticipantBotHandler) endGuestFlow(ctx context.Context, userState *model.UserState, text string) {
+	event := userState.CurrentEvent
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.GuestAllowance = 0
+	userState.GuestCount = 0
+	userState.GuestNames = nil
+	userState.GuestIndex = 0
+	userState.RSVPQuestionIndex = 0
+
+	p.sendGuestPrompt(ctx, text)
+	p.finishSignUp(ctx, userState, event)
+}
+
+func (p *ParticipantBotHandler) sendGuestPrompt(ctx context.Context, text string) {
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: &models.ReplyKeyboardRemove{RemoveKeyboard: true},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
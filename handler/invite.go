@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// joinLink builds the participant bot deep link that joins an event, or redeems an invite when given its token
+func joinLink(param string) string {
+	participantBotUsername := os.Getenv("PARTICIPANT_BOT_NAME")
+	if participantBotUsername == "" {
+		participantBotUsername = "your_participant_bot" // Fallback if not set
+	}
+	return fmt.Sprintf("https://t.me/%s?start=join_%s", participantBotUsername, param)
+}
+
+func (o *OrganiserBotHandler) handleCreateInvite(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		resetInviteState(userState)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		resetInviteState(userState)
+		return "You don't have permission to create invites for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		resetInviteState(userState)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	userState.CurrentEvent = event
+	userState.TempOptions = nil
+	userState.State = model.StateEnteringInviteLabel
+	return fmt.Sprintf("Creating an invite for '%s'. What label should it have, e.g. 'VIP' or 'Partner org'? Reply 'skip' for no label, or 'Cancel'.", event.Name)
+}
+
+func (o *OrganiserBotHandler) handleEnteringInviteLabel(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	label := strings.TrimSpace(update.Message.Text)
+	if label == "Cancel" {
+		resetInviteState(userState)
+		return "Invite creation cancelled."
+	}
+	if strings.EqualFold(label, "skip") {
+		label = ""
+	}
+
+	userState.TempOptions = []string{label}
+	userState.State = model.StateEnteringInviteMaxUses
+	return "How many people can join with this invite? Reply with a number, e.g. 1 for a single-use invite, or 'skip' for unlimited."
+}
+
+func (o *OrganiserBotHandler) handleEnteringInviteMaxUses(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	text := strings.TrimSpace(update.Message.Text)
+	if text == "Cancel" {
+		resetInviteState(userState)
+		return "Invite creation cancelled."
+	}
+
+	maxUses := 0
+	if !strings.EqualFold(text, "skip") {
+		var err error
+		maxUses, err = strconv.Atoi(text)
+		if err != nil || maxUses < 1 {
+			return "Please reply with a whole number of at least 1, or 'skip' for unlimited."
+		}
+	}
+
+	userState.TempOptions = append(userState.TempOptions, strconv.Itoa(maxUses))
+	userState.State = model.StateEnteringInviteExpiry
+	return fmt.Sprintf("When should this invite expire? Reply in the format YYYY-MM-DD HH:MM (%s), or 'skip' for never.",
+		o.organiserLocation(ctx, update.Message.From.ID))
+}
+
+func (o *OrganiserBotHandler) handleEnteringInviteExpiry(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	text := strings.TrimSpace(update.Message.Text)
+	if text == "Cancel" {
+		resetInviteState(userState)
+		return "Invite creation cancelled."
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	var expiresAt time.Time
+	if !strings.EqualFold(text, "skip") {
+		var err error
+		expiresAt, err = time.ParseInLocation(blastTimeLayout, text, loc)
+		if err != nil {
+			return "Invalid time format. Please use 'YYYY-MM-DD HH:MM' (e.g., 2023-12-25 18:30), or 'skip'."
+		}
+		if !expiresAt.After(time.Now()) {
+			return "The expiry must be in the future. Please enter a later time, or 'skip'."
+		}
+	}
+
+	maxUses, _ := strconv.Atoi(userState.TempOptions[1])
+	invite := model.Invite{
+		EventID:   userState.CurrentEvent.ID,
+		Label:     userState.TempOptions[0],
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt.UTC(),
+		CreatedBy: update.Message.From.ID,
+		CreatedAt: time.Now().UTC(),
+	}
+	event := userState.CurrentEvent
+	resetInviteState(userState)
+
+	token, err := o.FirebaseConnector.CreateInvite(ctx, invite)
+	if err != nil {
+		log.Println("error creating invite:", err)
+		return "Error creating the invite. Please try again."
+	}
+	invite.Token = token
+
+	return fmt.Sprintf("Invite created for '%s':\n%s\n\nShare this link:\n%s\n\nRevoke it any time with /revokeInvite %s",
+		event.Name, describeInvite(&invite, loc, time.Now()), joinLink(token), token)
+}
+
+func (o *OrganiserBotHandler) handleListInvites(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view invites for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	invites, err := o.FirebaseConnector.ListInvitesByEventID(ctx, eventID)
+	if err != nil {
+		log.Println("error listing invites:", err)
+		return "Error retrieving invites. Please try again."
+	}
+	if len(invites) == 0 {
+		return fmt.Sprintf("'%s' has no invites. Create one with /createInvite %s.", event.Name, eventID)
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+	usedBy := make(map[string][]string)
+	for i := range participants {
+		if signedUpEvent := findSignedUpEvent(&participants[i], eventID); signedUpEvent != nil && signedUpEvent.InviteToken != "" {
+			usedBy[signedUpEvent.InviteToken] = append(usedBy[signedUpEvent.InviteToken], participants[i].Name)
+		}
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	now := time.Now()
+	text := fmt.Sprintf("Invites for '%s':\n", event.Name)
+	if event.InviteOnly {
+		text += "This event is invite-only.\n"
+	}
+	for i := range invites {
+		invite := &invites[i]
+		text += fmt.Sprintf("\n%d. %s\n", i+1, describeInvite(invite, loc, now))
+		if names := usedBy[invite.Token]; len(names) > 0 {
+			text += fmt.Sprintf("   Joined with it: %s\n", strings.Join(names, ", "))
+		}
+		if inviteStatus(invite, now) == "active" {
+			text += fmt.Sprintf("   Link: %s\n   Revoke: /revokeInvite %s\n", joinLink(invite.Token), invite.Token)
+		}
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleRevokeInvite(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	token := strings.TrimSpace(update.Message.Text)
+
+	invite, err := o.FirebaseConnector.ReadInvite(ctx, token)
+	if errors.Is(err, model.ErrInviteDoesNotExist) {
+		return fmt.Sprintf("No invite found with token '%s'.", token)
+	} else if err != nil {
+		log.Println("error reading invite:", err)
+		return "Error retrieving the invite. Please try again."
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, invite.EventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return "Error checking your permissions. Please try again."
+	}
+	if !allowed {
+		return "You don't have permission to revoke invites for this event."
+	}
+
+	if invite.Revoked {
+		return "This invite has already been revoked."
+	}
+	invite.Revoked = true
+	err = o.FirebaseConnector.UpdateInvite(ctx, *invite)
+	if err != nil {
+		log.Println("error revoking invite:", err)
+		return "Error revoking the invite. Please try again."
+	}
+	return fmt.Sprintf("Invite %s revoked. People who already joined with it stay registered.", describeInviteLabel(invite))
+}
+
+func (o *OrganiserBotHandler) handleSettingInviteOnly(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, inviteOnly, errText := parseEventToggle(update.Message.Text)
+	if errText != "" {
+		return errText
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to change how people join this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	event.InviteOnly = inviteOnly
+	err = o.FirebaseConnector.UpdateEvent(ctx, eventID, *event)
+	if err != nil {
+		log.Println("error updating event:", err)
+		return "Error updating the event. Please try again."
+	}
+
+	if inviteOnly {
+		return fmt.Sprintf("'%s' is now invite-only. The public join link and reference code no longer let people join; create invites with /createInvite %s.", event.Name, eventID)
+	}
+	return fmt.Sprintf("Anyone with the public join link or reference code can join '%s' again.", event.Name)
+}
+
+// redeemInvite uses up the invite a user is joining with, or refuses an invite-only event joined without one.
+// Users already signed up don't use up an invite. errText is set when the user may not join.
+func (p *ParticipantBotHandler) redeemInvite(ctx context.Context, event *model.Event, invite *model.Invite, userID int64) (*model.Invite, string) {
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+	if err != nil {
+		log.Println("error reading participant:", err)
+	} else if participant != nil && findSignedUpEvent(participant, event.ID) != nil {
+		return nil, ""
+	}
+
+	if invite == nil {
+		return nil, fmt.Sprintf("'%s' is invite-only. Please join using the invite link you were given.", event.Name)
+	}
+
+	redeemed, err := p.FirebaseConnector.RedeemInvite(ctx, invite.Token, time.Now())
+	switch {
+	case errors.Is(err, model.ErrInviteRevoked), errors.Is(err, model.ErrInviteDoesNotExist):
+		return nil, "This invite link is no longer valid. Please ask the organiser for a new one."
+	case errors.Is(err, model.ErrInviteExpired):
+		return nil, "This invite link has expired. Please ask the organiser for a new one."
+	case errors.Is(err, model.ErrInviteUsedUp):
+		return nil, "This invite link has already been used the maximum number of times. Please ask the organiser for a new one."
+	case err != nil:
+		log.Println("error redeeming invite:", err)
+		return nil, "Error checking your invite. Please try again."
+	}
+	return redeemed, ""
+}
+
+// inviteStatus reports whether an invite can still be used, and if not why
+func inviteStatus(invite *model.Invite, now time.Time) string {
+	switch {
+	case invite.Revoked:
+		return "revoked"
+	case !invite.ExpiresAt.IsZero() && !now.Before(invite.ExpiresAt):
+		return "expired"
+	case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+		return "used up"
+	default:
+		return "active"
+	}
+}
+
+// describeInvite renders an invite's label, status, uses and expiry on one line
+func describeInvite(invite *model.Invite, loc *time.Location, now time.Time) string {
+	text := fmt.Sprintf("%s – %s, ", describeInviteLabel(invite), inviteStatus(invite, now))
+	if invite.MaxUses > 0 {
+		text += fmt.Sprintf("%d/%d uses", invite.Uses, invite.MaxUses)
+	} else {
+		text += fmt.Sprintf("%d uses (unlimited)", invite.Uses)
+	}
+	if !invite.ExpiresAt.IsZero() {
+		text += ", expires " + invite.ExpiresAt.In(loc).Format(blastTimeLayout)
+	}
+	return text
+}
+
+func describeInviteLabel(invite *model.Invite) string {
+	if invite.Label == "" {
+		return "(no label)"
+	}
+	return fmt.Sprintf("'%s'", invite.Label)
+}
+
+// formatInviteUsed names the invite a participant joined with for exports, preferring its label
+func formatInviteUsed(signedUpEvent *model.SignedUpEvent) string {
+	if signedUpEvent.InviteLabel != "" {
+		return signedUpEvent.InviteLabel
+	}
+	return signedUpEvent.InviteToken
+}
+
+// parseEventToggle parses "EVENT_REF_CODE on|off" input; errText is set on failure
+func parseEventToggle(text string) (string, bool, string) {
+	parts := strings.Fields(text)
+	if len(parts) != 2 || (parts[1] != "on" && parts[1] != "off") {
+		return "", false, "Invalid format. Please use: EVENT_REF_CODE on|off\nExample: ABC123 on"
+	}
+	return parts[0], parts[1] == "on", ""
+}
+
+func resetInviteState(userState *model.UserState) {
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.TempOptions = nil
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var eventStatusLabels = map[model.EventStatus]string{
+	model.EventStatusDraft:     "Draft (not joinable yet)",
+	model.EventStatusPublished: "Published",
+	model.EventStatusCancelled: "Cancelled",
+	model.EventStatusCompleted: "Completed",
+}
+
+// describeEventStatus renders the event's status, with the reason if it was cancelled
+func describeEventStatus(event *model.Event) string {
+	text := eventStatusLabels[event.CurrentStatus()]
+	if event.CurrentStatus() == model.EventStatusCancelled && event.CancelReason != "" {
+		text += fmt.Sprintf(" (%s)", event.CancelReason)
+	}
+	return text
+}
+
+// joinBlockedText returns why participants can't join the event, or "" if they can
+func joinBlockedText(event *model.Event) string {
+	if event.IsDeleted() {
+		return fmt.Sprintf("Sorry, '%s' is no longer available.", event.Name)
+	}
+	switch event.CurrentStatus() {
+	case model.EventStatusDraft:
+		return fmt.Sprintf("'%s' isn't open for sign-ups yet. Please try again once the organiser has published it.", event.Name)
+	case model.EventStatusCancelled:
+		return fmt.Sprintf("Sorry, '%s' has been cancelled.", event.Name)
+	case model.EventStatusCompleted:
+		return fmt.Sprintf("'%s' has already taken place.", event.Name)
+	}
+	return ""
+}
+
+// checkInBlockedText returns why no one can check in to the event in its current status, or "" if they can
+func checkInBlockedText(event *model.Event) string {
+	if event.IsDeleted() {
+		return fmt.Sprintf("'%s' has been deleted, so check-in is closed.", event.Name)
+	}
+	switch event.CurrentStatus() {
+	case model.EventStatusDraft:
+		return fmt.Sprintf("'%s' hasn't been published yet, so check-in is not open.", event.Name)
+	case model.EventStatusCancelled:
+		return fmt.Sprintf("'%s' has been cancelled, so check-in is closed.", event.Name)
+	case model.EventStatusCompleted:
+		return fmt.Sprintf("'%s' is completed, so check-in is closed.", event.Name)
+	}
+	return ""
+}
+
+// readEventForStatusChange checks the organiser's permission and reads the event whose status they want to change
+func (o *OrganiserBotHandler) readEventForStatusChange(ctx context.Context, eventID string, organiserID int64, permission model.Permission, action string) (*model.Event, string) {
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return nil, fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	if !event.RoleOf(organiserID).Can(permission) {
+		return nil, fmt.Sprintf("You don't have permission to %s this event.", action)
+	}
+	return event, ""
+}
+
+func (o *OrganiserBotHandler) handlePublishingEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	event, errText := o.readEventForStatusChange(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent, "publish")
+	if errText != "" {
+		return errText
+	}
+
+	published, err := o.FirebaseConnector.SetEventStatus(ctx, eventID, model.EventStatusPublished, "", time.Now())
+	if errors.Is(err, model.ErrInvalidStatusChange) {
+		return fmt.Sprintf("'%s' can't be published because it is %s.", event.Name, strings.ToLower(describeEventStatus(event)))
+	} else if err != nil {
+		log.Println("error publishing event:", err)
+		return "Error publishing the event. Please try again."
+	}
+
+	text := fmt.Sprintf("'%s' is now published! 🎉\n\nReference Code: %s\n\nParticipants can join using this link:\n%s", published.Name, eventID, joinLink(eventID))
+	if published.SeriesID != "" {
+		published.ID = eventID
+		text += fmt.Sprintf("\n\n%d other sessions of the series were published too.", o.publishSeriesDrafts(ctx, published))
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleCancellingEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, reason, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	event, errText := o.readEventForStatusChange(ctx, eventID, update.Message.From.ID, model.PermissionDeleteEvent, "cancel")
+	if errText != "" {
+		return errText
+	}
+	if !event.CurrentStatus().CanBecome(model.EventStatusCancelled) {
+		return fmt.Sprintf("'%s' can't be cancelled because it is %s.", event.Name, strings.ToLower(describeEventStatus(event)))
+	}
+
+	userState.CurrentEvent = event
+	userState.TempOptions = []string{strings.TrimSpace(reason)}
+	userState.State = model.StateConfirmCancelEvent
+
+	text := fmt.Sprintf("Cancel '%s' on %s?\n", event.Name, event.EventDate.Format("2006-01-02"))
+	if event.CurrentStatus() == model.EventStatusPublished {
+		text += fmt.Sprintf("All %d participants will be told", len(event.Participants)+len(event.Applicants))
+		if reason != "" {
+			text += fmt.Sprintf(" with the reason: %s", strings.TrimSpace(reason))
+		}
+		text += ", and check-in will be closed.\n"
+	}
+	return text + "This can't be undone. Reply 'yes' to cancel the event or 'no' to keep it."
+}
+
+func (o *OrganiserBotHandler) handleConfirmCancelEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	event := userState.CurrentEvent
+	reason := userState.TempOptions[0]
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.TempOptions = nil
+
+	if strings.ToLower(strings.TrimSpace(update.Message.Text)) != "yes" {
+		return fmt.Sprintf("'%s' has not been cancelled.", event.Name)
+	}
+
+	_, err := o.FirebaseConnector.SetEventStatus(ctx, event.ID, model.EventStatusCancelled, reason, time.Now())
+	if errors.Is(err, model.ErrInvalidStatusChange) {
+		return "The event can no longer be cancelled. It may already have been cancelled or completed."
+	} else if err != nil {
+		log.Println("error cancelling event:", err)
+		return "Error cancelling the event. Please try again."
+	}
+	notifyCheckIn(event.ID)
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", event.ID, err)
+		return fmt.Sprintf("'%s' has been cancelled, but participants could not be retrieved to notify them. Please tell them with /blast %s.", event.Name, event.ID)
+	}
+	applicants, err := o.FirebaseConnector.ListApplicants(ctx, event.ID)
+	if err != nil {
+		log.Printf("error reading applicants for event(ID: %s): %v\n", event.ID, err)
+	}
+	participants = append(participants, applicants...)
+	if len(participants) == 0 {
+		return fmt.Sprintf("'%s' has been cancelled. No one had joined it yet.", event.Name)
+	}
+
+	notice := fmt.Sprintf("❌ '%s' on %s has been cancelled by the organiser.", event.Name, event.EventDate.Format("2006-01-02"))
+	if reason != "" {
+		notice += "\nReason: " + reason
+	}
+
+	participantBot, err := newParticipantBot()
+	if err != nil {
+		log.Println("error creating participant bot:", err)
+		return fmt.Sprintf("'%s' has been cancelled, but participants could not be notified. Please tell them with /blast %s.", event.Name, event.ID)
+	}
+	notified := 0
+	for _, participant := range participants {
+		_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: participant.UserID,
+			Text:   notice,
+		})
+		if err != nil {
+			log.Printf("error notifying participant %d of cancellation: %v", participant.UserID, err)
+			continue
+		}
+		notified++
+	}
+	return fmt.Sprintf("'%s' has been cancelled. %d of %d participants have been notified.", event.Name, notified, len(participants))
+}
+
+func (o *OrganiserBotHandler) handleCompletingEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	event, errText := o.readEventForStatusChange(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent, "complete")
+	if errText != "" {
+		return errText
+	}
+
+	_, err := o.FirebaseConnector.SetEventStatus(ctx, eventID, model.EventStatusCompleted, "", time.Now())
+	if errors.Is(err, model.ErrInvalidStatusChange) {
+		return fmt.Sprintf("Only published events can be completed, and '%s' is %s.", event.Name, strings.ToLower(describeEventStatus(event)))
+	} else if err != nil {
+		log.Println("error completing event:", err)
+		return "Error completing the event. Please try again."
+	}
+	notifyCheckIn(eventID)
+
+	return fmt.Sprintf("'%s' is now completed and read-only. It has moved to /pastEvents, and /attendanceReport %s still works.", event.Name, eventID)
+}
+
+func (o *OrganiserBotHandler) handleListPastEvents(ctx context.Context, userID int64) string {
+	events, err := o.FirebaseConnector.ListEventsByUserID(ctx, userID)
+	if err != nil {
+		log.Println("error listing events:", err)
+		return "Error retrieving your events. Please try again."
+	}
+
+	text := "Your completed events:\n"
+	found := false
+	for _, event := range events {
+		if event.CurrentStatus() != model.EventStatusCompleted || event.IsDeleted() {
+			continue
+		}
+		found = true
+		text += fmt.Sprintf("- %s (Reference Code: %s)\n", event.Name, event.ID)
+		text += fmt.Sprintf("  Date: %s\n", event.EventDate.Format("2006-01-02"))
+		text += fmt.Sprintf("  Participants: %d\n", len(event.Participants))
+	}
+	if !found {
+		return "You have no completed events. Mark an event as done with /completeEvent."
+	}
+	return text + "\nCompleted events are read-only. Use /attendanceReport or /exportParticipants to look back at them."
+}
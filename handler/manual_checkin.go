@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// Name searches matching more participants than this ask the organiser to narrow it down
+const maxManualCheckInMatches = 10
+
+func (o *OrganiserBotHandler) handleManualCheckIn(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.findManualCheckInParticipant(ctx, update, userState, false)
+}
+
+func (o *OrganiserBotHandler) handleUndoCheckIn(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	return o.findManualCheckInParticipant(ctx, update, userState, true)
+}
+
+// findManualCheckInParticipant looks up the participant named in "EVENT_REF_CODE NAME_OR_USER_ID" and checks them in or undoes it,
+// asking the organiser to pick when the name matches several participants
+func (o *OrganiserBotHandler) findManualCheckInParticipant(ctx context.Context, update *models.Update, userState *model.UserState, undo bool) string {
+	userState.State = model.StateIdle
+
+	parts := strings.SplitN(strings.TrimSpace(update.Message.Text), " ", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+		return "Invalid format. Please use: EVENT_REF_CODE NAME_OR_USER_ID\nExample: ABC123 Alice"
+	}
+	eventID, query := parts[0], strings.TrimSpace(parts[1])
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionCheckIn)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to check participants in manually for this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	if blockedText := checkInBlockedText(event); blockedText != "" && !undo {
+		return blockedText
+	}
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+
+	matches := matchParticipants(participants, query)
+	switch {
+	case len(matches) == 0:
+		return fmt.Sprintf("No participant of '%s' matches '%s'.", event.Name, query)
+	case len(matches) == 1:
+		return o.applyManualCheckIn(ctx, event, &matches[0], update.Message.From.ID, undo, true)
+	case len(matches) > maxManualCheckInMatches:
+		return fmt.Sprintf("%d participants match '%s'. Please be more specific or use their User ID.", len(matches), query)
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	text := fmt.Sprintf("Several participants match '%s'. Reply with the number of the right one, or 'Cancel':\n", query)
+	userState.TempOptions = nil
+	for i := range matches {
+		text += fmt.Sprintf("%d. %s\n", i+1, describeCheckInStatus(&matches[i], event.ID, loc))
+		userState.TempOptions = append(userState.TempOptions, matches[i].ID)
+	}
+
+	userState.CurrentEvent = event
+	if undo {
+		userState.State = model.StateSelectUndoCheckInMatch
+	} else {
+		userState.State = model.StateSelectManualCheckInMatch
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleSelectManualCheckInMatch(ctx context.Context, update *models.Update, userState *model.UserState, undo bool) string {
+	if update.Message.Text == "Cancel" {
+		resetManualCheckInState(userState)
+		return "Operation cancelled."
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(update.Message.Text))
+	if err != nil || choice < 1 || choice > len(userState.TempOptions) {
+		return fmt.Sprintf("Please reply with a number between 1 and %d, or 'Cancel'.", len(userState.TempOptions))
+	}
+
+	event := userState.CurrentEvent
+	participantID := userState.TempOptions[choice-1]
+	resetManualCheckInState(userState)
+
+	participant, err := o.FirebaseConnector.ReadParticipantByID(ctx, participantID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		return "Error retrieving the participant. Please try again."
+	}
+	return o.applyManualCheckIn(ctx, event, participant, update.Message.From.ID, undo, true)
+}
+
+// applyManualCheckIn checks the participant in (or undoes their check-in) on the organiser's behalf and records it in the audit log.
+// With guestHint set a successful check-in also lists the participant's guests who haven't arrived yet.
+func (o *OrganiserBotHandler) applyManualCheckIn(ctx context.Context, event *model.Event, participant *model.Participant, organiserID int64, undo bool, guestHint bool) string {
+	loc := o.organiserLocation(ctx, organiserID)
+	now := time.Now()
+
+	var action model.AuditAction
+	var text string
+	if undo {
+		previous := findSignedUpEvent(participant, event.ID)
+		_, err := o.FirebaseConnector.UndoCheckIn(ctx, participant.ID, event.ID)
+		if errors.Is(err, model.ErrNotCheckedIn) {
+			return fmt.Sprintf("%s is not checked in to '%s'.", participant.Name, event.Name)
+		} else if errors.Is(err, model.ErrNotSignedUp) {
+			return fmt.Sprintf("%s is not registered for '%s'.", participant.Name, event.Name)
+		} else if err != nil {
+			log.Println("error undoing check-in:", err)
+			return "Error undoing the check-in. Please try again."
+		}
+
+		action = model.AuditActionCheckInUndone
+		text = fmt.Sprintf("↩️ Check-in of %s undone.", participant.Name)
+		if previous != nil && !previous.CheckedInAt.IsZero() {
+			text = fmt.Sprintf("↩️ Check-in of %s (at %s) undone.", participant.Name, previous.CheckedInAt.In(loc).Format(exportTimeLayout))
+		}
+	} else {
+		_, err := o.FirebaseConnector.CheckInParticipant(ctx, participant.ID, event.ID, now, organiserID)
+		if errors.Is(err, model.ErrAlreadyCheckedIn) {
+			return fmt.Sprintf("⚠️ %s", describeCheckInStatus(participant, event.ID, loc))
+		} else if errors.Is(err, model.ErrNotSignedUp) {
+			return fmt.Sprintf("%s is not registered for '%s'.", participant.Name, event.Name)
+		} else if err != nil {
+			log.Println("error checking in participant:", err)
+			return "Error checking the participant in. Please try again."
+		}
+
+		action = model.AuditActionManualCheckIn
+		text = fmt.Sprintf("✅ %s checked in at %s.", participant.Name, now.In(loc).Format(exportTimeLayout))
+		if guestHint {
+			text += guestCheckInHint(event, participant)
+		}
+	}
+
+	notifyCheckIn(event.ID)
+
+	err := o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      event.ID,
+		Action:       action,
+		ActorID:      organiserID,
+		TargetUserID: participant.UserID,
+		At:           now.UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+	return text
+}
+
+// matchParticipants finds participants by user ID, or by name or @username; exact matches win over partial ones
+func matchParticipants(participants []model.Participant, query string) []model.Participant {
+	if userID, err := strconv.ParseInt(query, 10, 64); err == nil {
+		for _, participant := range participants {
+			if participant.UserID == userID {
+				return []model.Participant{participant}
+			}
+		}
+		return nil
+	}
+
+	query = strings.ToLower(strings.TrimPrefix(query, "@"))
+	var exact, partial []model.Participant
+	for _, participant := range participants {
+		name := strings.ToLower(participant.Name)
+		username := strings.ToLower(participant.Username)
+		if name == query || (username != "" && username == query) {
+			exact = append(exact, participant)
+		} else if strings.Contains(name, query) || (username != "" && strings.Contains(username, query)) {
+			partial = append(partial, participant)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+// describeCheckInStatus renders a participant with whether, when and by whom they were checked in
+func describeCheckInStatus(participant *model.Participant, eventID string, loc *time.Location) string {
+	text := participant.Name
+	if participant.Username != "" {
+		text += " (@" + participant.Username + ")"
+	}
+	text += fmt.Sprintf(" [ID: %d]", participant.UserID)
+
+	signedUpEvent := findSignedUpEvent(participant, eventID)
+	if signedUpEvent == nil || !signedUpEvent.CheckedIn {
+		return text + " – not checked in" + describeGuestCount(signedUpEvent)
+	}
+
+	text += " – checked in"
+	if !signedUpEvent.CheckedInAt.IsZero() {
+		text += " at " + signedUpEvent.CheckedInAt.In(loc).Format(exportTimeLayout)
+	}
+	switch signedUpEvent.CheckedInBy {
+	case 0:
+	case participant.UserID:
+		text += " (self check-in)"
+	default:
+		text += fmt.Sprintf(" by %d", signedUpEvent.CheckedInBy)
+	}
+	return text + describeGuestCount(signedUpEvent)
+}
+
+func resetManualCheckInState(userState *model.UserState) {
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userState.TempOptions = nil
+}
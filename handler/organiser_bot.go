@@ -6,8 +6,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -57,18 +55,88 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 
 	switch userState.State {
 	case model.StateIdle:
-		switch update.Message.Text {
+		command, arg := splitCommand(update.Message.Text)
+		switch command {
 		case "/start":
+			if token, ok := strings.CutPrefix(arg, "coown_"); ok {
+				params = &bot.SendMessageParams{
+					ChatID:      chatID,
+					Text:        o.handleAcceptCoownerInvite(ctx, b, update, token),
+					ReplyMarkup: getOrganizerMainMenuKeyboard(),
+				}
+				_, err := b.SendMessage(ctx, params)
+				if err != nil {
+					log.Println("error sending message:", err)
+				}
+				return
+			}
 			text = `Hello! I'm your EventBot. Use the following commands to manage events:
 /addEvent - Create a new event with details and RSVP questions
 /editEvent - Edit an existing event
+/cloneEvent <Event_Reference_Code> - Copy an event into a new draft with a new name and date
+/saveTemplate <Event_Reference_Code> <Name> - Save an event as a template to start new events from
+/templates - List your templates
+/deleteTemplate <Name> - Delete a template
+/createSeries <Event_Reference_Code> <Rule> - Repeat an event weekly, e.g. every Tuesday at 19:00 for 10 weeks
+/seriesAttendance <Event_Reference_Code> - See attendance across every session of a series
+/addSession <Event_Reference_Code> <Title | 09:00-10:30 | Room | Speaker | Capacity> - Add a session to an event's agenda
+/agenda <Event_Reference_Code> - See an event's agenda with sign-ups and check-ins
+/removeSession <Event_Reference_Code> <Session_Number> - Remove a session from the agenda
+/sessionRoster <Event_Reference_Code> <Session_Number> - See who signed up for a session and who has arrived
+/checkInSession <Event_Reference_Code> <Session_Number> <Name_or_User_ID> - Check a participant in at a session
+/undoSessionCheckIn <Event_Reference_Code> <Session_Number> <Name_or_User_ID> - Undo a session check-in
+/setGuests <Event_Reference_Code> <Max_Guests> [rsvp] - Let participants bring guests, optionally answering the RSVP questions for each
+/setCapacity <Event_Reference_Code> <Places|off> - Limit how many people, guests included, can sign up
+/checkInGuests <Event_Reference_Code> <Name_or_User_ID> [all|Guest_Numbers] - Check in a participant's whole party or some of their guests
+/undoGuestCheckIn <Event_Reference_Code> <Name_or_User_ID> [all|Guest_Numbers] - Undo guest check-ins
+/publishEvent <Event_Reference_Code> - Open a draft event for sign-ups and get its join link
+/cancelEvent <Event_Reference_Code> [Reason] - Cancel an event and tell its participants
+/completeEvent <Event_Reference_Code> - Mark an event as done and make it read-only
+/pastEvents - View your completed events
 /deleteEvent <Event_Reference_Code> - Delete an existing event
+/restoreEvent <Event_Reference_Code> - Bring back a recently deleted event
 /listParticipants <Event_Reference_Code> - List participants of an event
 /blast <Event_Reference_Code> - Send a message to all participants
 /viewEvents - View all your events
 /setCheckInCode <Event_Reference_Code> - Set or update the check-in code for an event
-/addCoowner <Event_Reference_Code> <User_ID> - Add a coowner to an event
+/checkInCode <Event_Reference_Code> - Show the current check-in code
+/liveCheckInCode <Event_Reference_Code> - Post a check-in code that updates as it rotates
+/stopLiveCheckInCode - Stop updating the live check-in code
+/setCheckInWindows <Event_Reference_Code> - Choose when participants can check in
+/addCoowner <Event_Reference_Code> <User_ID> [Role] - Add a coowner to an event as admin, editor, messenger, door or viewer
 /removeCoowner <Event_Reference_Code> <User_ID> - Remove a coowner from an event
+/setRole <Event_Reference_Code> <User_ID> <Role> - Change a coowner's role
+/inviteCoowner <Event_Reference_Code> [Role] - Create a one-time link that adds a coowner
+/listCoowners <Event_Reference_Code> - See the event's team and their roles
+/transferOwnership <Event_Reference_Code> <User_ID> - Hand the event over to a coowner
+/acceptOwnership <Event_Reference_Code> - Accept an event you were asked to take over
+/declineOwnership <Event_Reference_Code> - Decline an event you were asked to take over
+/exportParticipants <Event_Reference_Code> - Download participants and RSVP answers as CSV
+/rsvpSummary <Event_Reference_Code> - See RSVP answer counts and response rates
+/attendanceReport <Event_Reference_Code> - See who attended, no-shows and walk-ins
+/scanTickets <Event_Reference_Code> - Check participants in by scanning their QR tickets
+/checkInParticipant <Event_Reference_Code> <Name_or_User_ID> - Check a participant in manually
+/undoCheckIn <Event_Reference_Code> <Name_or_User_ID> - Undo a mistaken check-in
+/removeParticipant <Event_Reference_Code> <Name_or_User_ID> - Remove a participant from an event
+/ban <User_ID> [Reason] - Stop someone from joining any of your events
+/unban <User_ID> - Lift a ban
+/banList - List the users you have banned
+/requireApproval <Event_Reference_Code> <on|off> - Make people apply to join an event
+/applications <Event_Reference_Code> - Review applications waiting for approval
+/approve <Event_Reference_Code> <User_ID> - Approve an application
+/reject <Event_Reference_Code> <User_ID> [Reason] - Reject an application
+/createInvite <Event_Reference_Code> - Create an invite link with an optional label, use limit and expiry
+/invites <Event_Reference_Code> - List an event's invites and who joined with each
+/revokeInvite <Invite_Token> - Stop an invite link from working
+/inviteOnly <Event_Reference_Code> <on|off> - Only let people join through invites
+/checkInDashboard <Event_Reference_Code> - Post a live dashboard of who has arrived
+/stopCheckInDashboard - Stop updating the check-in dashboard
+/addChecker <Event_Reference_Code> <User_ID> - Allow someone to scan tickets for an event
+/removeChecker <Event_Reference_Code> <User_ID> - Stop someone from scanning tickets
+/scheduledBlasts <Event_Reference_Code> - List scheduled blasts for an event
+/editBlast <Blast_ID> - Edit a scheduled blast
+/cancelBlast <Blast_ID> - Cancel a scheduled blast
+/setTimezone <Zone> - Set your timezone for scheduling (e.g. Asia/Singapore)
 /myid - Get your Telegram User ID
 /help - Show this help message`
 
@@ -100,6 +168,10 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			text = "Please provide the Reference Code of the event you want to set a check-in code for."
 			userState.State = model.StateSettingEventCheckInCode
 		case "/addEvent":
+			userState.CurrentEvent = nil
+			if o.promptEventTemplate(ctx, b, chatID, update.Message.From.ID, userState) {
+				return
+			}
 			text = "Okay, let's create a new event. What's the name of the event?"
 
 			// Add a Cancel button
@@ -165,10 +237,24 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			userState.CurrentRSVPQuestion = nil
 			userState.RSVPQuestionIndex = 0
 			userState.TempOptions = nil
+			userState.CurrentBlast = nil
 			return
 		case "/deleteEvent":
-			text = "Okay, let's delete an event. Please provide the Reference Code of the event you want to delete."
 			userState.State = model.StateDeleteEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleDeleteEvent(ctx, update, userState)
+			} else {
+				text = "Okay, let's delete an event. Please provide the Reference Code of the event you want to delete."
+			}
+		case "/restoreEvent":
+			userState.State = model.StateRestoringEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRestoringEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the deleted event you want to restore."
+			}
 		case "/blast":
 			text = "Okay, let's send a message to all participants. Please provide the Reference Code of the event."
 			userState.State = model.StateBlastMessage
@@ -182,14 +268,36 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			} else {
 				text = "Here are your events:\n"
 				for _, event := range events {
+					if event.IsDeleted() {
+						text += fmt.Sprintf("- %s (Reference Code: %s)\n  Deleted, restore with /restoreEvent %s before %s\n",
+							event.Name, event.ID, event.ID, event.PurgeAt.In(o.organiserLocation(ctx, update.Message.From.ID)).Format(blastTimeLayout))
+						continue
+					}
+					// Completed events are listed by /pastEvents
+					if event.CurrentStatus() == model.EventStatusCompleted {
+						continue
+					}
 					text += fmt.Sprintf("- %s (Reference Code: %s)\n", event.Name, event.ID)
 					text += fmt.Sprintf("  Date: %s\n", event.EventDate.Format("2006-01-02"))
+					text += fmt.Sprintf("  Status: %s\n", describeEventStatus(&event))
+					if event.Venue != nil {
+						text += fmt.Sprintf("  Venue: %s\n", strings.ReplaceAll(formatVenue(event.Venue), "\n", ", "))
+					}
 
-					// Show check-in code if set
-					if event.CheckInCode != "" {
-						text += fmt.Sprintf("  Check-in Code: %s\n", event.CheckInCode)
-					} else {
-						text += "  Check-in Code: Not set (use /setCheckInCode)\n"
+					text += fmt.Sprintf("  Your Role: %s\n", event.RoleOf(update.Message.From.ID))
+					if len(event.Coowners) > 0 {
+						text += "  Team:\n" + describeTeam(&event)
+					}
+					if event.InviteOnly {
+						text += "  Invite Only: Yes\n"
+					}
+					if event.RequiresApproval || len(event.Applicants) > 0 {
+						text += fmt.Sprintf("  Approval Required: %s (%d waiting)\n", formatYesNo(event.RequiresApproval), len(event.Applicants))
+					}
+
+					// Show check-in code if set and the user's role may see it
+					if event.RoleOf(update.Message.From.ID).Can(model.PermissionCheckIn) {
+						text += fmt.Sprintf("  Check-in Code: %s\n", describeCheckInCode(&event))
 					}
 
 					if len(event.EventDetails) > 0 {
@@ -222,7 +330,9 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			text = "To add a coowner, you need their Telegram User ID. Here's how to get it:\n\n" +
 				"1. Ask the user to use the /myid command\n" +
 				"2. They will receive their unique Telegram User ID\n" +
-				"3. Use that ID when adding them as a coowner"
+				"3. Use that ID when adding them as a coowner\n\n" +
+				"Then send: EVENT_REF_CODE USER_ID [ROLE]\n" +
+				"Roles (admin if not given):\n" + describeCoownerRoles()
 
 			userState.State = model.StateAddingCoowner
 		case "/myid":
@@ -241,9 +351,440 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			text = "Please provide the event reference code and the Telegram User ID of the coowner to remove in the format: EVENT_REF_CODE USER_ID\n\n" +
 				"You can use /myid to help find User IDs"
 			userState.State = model.StateRemovingCoowner
+		case "/setRole":
+			userState.State = model.StateSettingCoownerRole
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingCoownerRole(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code, the coowner's User ID and their new role in the format: EVENT_REF_CODE USER_ID ROLE\n\nRoles:\n" + describeCoownerRoles()
+			}
+		case "/inviteCoowner":
+			userState.State = model.StateInvitingCoowner
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleInvitingCoowner(ctx, b, update, userState)
+			} else {
+				text = "Please provide the event reference code and, optionally, the role for the new coowner in the format: EVENT_REF_CODE [ROLE]\n\nRoles (admin if not given):\n" + describeCoownerRoles()
+			}
+		case "/cloneEvent":
+			userState.State = model.StateCloningEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCloningEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to copy."
+			}
+		case "/saveTemplate":
+			userState.State = model.StateSavingTemplate
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSavingTemplate(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and a name for the template in the format: EVENT_REF_CODE TEMPLATE_NAME"
+			}
+		case "/templates":
+			text = o.handleListTemplates(ctx, update.Message.From.ID)
+		case "/createSeries":
+			userState.State = model.StateCreatingSeries
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCreatingSeries(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and how often it repeats in the format: EVENT_REF_CODE every DAY at HH:MM for N weeks\n" +
+					"Example: ABC123 every Tuesday at 19:00 for 10 weeks\n\nThe event becomes the first session."
+			}
+		case "/addSession":
+			userState.State = model.StateAddingAgendaSession
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleAddingAgendaSession(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the session in the format: EVENT_REF_CODE Title | 09:00-10:30 | Room | Speaker | Capacity\n" +
+					"Room, speaker and capacity are optional.\nExample: ABC123 Opening keynote | 09:00-09:45 | Main hall | Dr Tan"
+			}
+		case "/agenda":
+			userState.State = model.StateViewingAgenda
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleViewingAgenda(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event."
+			}
+		case "/removeSession":
+			userState.State = model.StateRemovingAgendaSession
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRemovingAgendaSession(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the session number in the format: EVENT_REF_CODE SESSION_NUMBER"
+			}
+		case "/sessionRoster":
+			userState.State = model.StateViewingSessionRoster
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleViewingSessionRoster(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the session number in the format: EVENT_REF_CODE SESSION_NUMBER"
+			}
+		case "/checkInSession":
+			userState.State = model.StateCheckingInSession
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSessionCheckIn(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code, the session number and the participant's name or User ID in the format: EVENT_REF_CODE SESSION_NUMBER NAME_OR_USER_ID"
+			}
+		case "/undoSessionCheckIn":
+			userState.State = model.StateUndoingSessionCheckIn
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleUndoSessionCheckIn(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code, the session number and the participant's name or User ID in the format: EVENT_REF_CODE SESSION_NUMBER NAME_OR_USER_ID"
+			}
+		case "/setGuests":
+			userState.State = model.StateSettingGuestPolicy
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingGuestPolicy(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and how many guests each participant may bring in the format: EVENT_REF_CODE MAX_GUESTS [rsvp]\nAdd 'rsvp' to ask the RSVP questions for each guest too, or use 0 to turn guests off."
+			}
+		case "/setCapacity":
+			userState.State = model.StateSettingCapacity
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingCapacity(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the number of places, guests included, in the format: EVENT_REF_CODE PLACES\nUse 'off' instead of a number to remove the limit."
+			}
+		case "/checkInGuests":
+			userState.State = model.StateCheckingInGuests
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCheckInGuests(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the participant's name or User ID in the format: EVENT_REF_CODE NAME_OR_USER_ID [all|GUEST_NUMBERS]"
+			}
+		case "/undoGuestCheckIn":
+			userState.State = model.StateUndoingGuestCheckIn
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleUndoGuestCheckIn(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the participant's name or User ID in the format: EVENT_REF_CODE NAME_OR_USER_ID [all|GUEST_NUMBERS]"
+			}
+		case "/seriesAttendance":
+			userState.State = model.StateViewingSeriesAttendance
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSeriesAttendance(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of any session in the series."
+			}
+		case "/deleteTemplate":
+			userState.State = model.StateDeletingTemplate
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleDeletingTemplate(ctx, update, userState)
+			} else {
+				text = "Please provide the name of the template you want to delete."
+			}
+		case "/publishEvent":
+			userState.State = model.StatePublishingEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handlePublishingEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the draft event you want to publish."
+			}
+		case "/cancelEvent":
+			userState.State = model.StateCancellingEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCancellingEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to cancel and, optionally, a reason for participants in the format: EVENT_REF_CODE [REASON]"
+			}
+		case "/completeEvent":
+			userState.State = model.StateCompletingEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCompletingEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event that has finished."
+			}
+		case "/pastEvents":
+			text = o.handleListPastEvents(ctx, update.Message.From.ID)
+		case "/transferOwnership":
+			userState.State = model.StateTransferringOwnership
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleTransferringOwnership(ctx, b, update, userState)
+			} else {
+				text = "Please provide the event reference code and the User ID of the coowner who should become the owner in the format: EVENT_REF_CODE USER_ID\n\n" +
+					"They will be asked to confirm. To withdraw a pending transfer, send: EVENT_REF_CODE cancel"
+			}
+		case "/acceptOwnership":
+			userState.State = model.StateAcceptingOwnership
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleAcceptingOwnership(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you were asked to take over."
+			}
+		case "/declineOwnership":
+			userState.State = model.StateDecliningOwnership
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleDecliningOwnership(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you were asked to take over."
+			}
+		case "/listCoowners":
+			userState.State = model.StateListingCoowners
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleListCoowners(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event."
+			}
 		case "/editEvent":
 			text = "Please provide the Reference Code of the event you want to edit."
 			userState.State = model.StateEditEvent
+		case "/exportParticipants":
+			userState.State = model.StateExportParticipants
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleExportParticipants(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to export participants for."
+			}
+		case "/rsvpSummary":
+			userState.State = model.StateRSVPSummary
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRSVPSummary(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want an RSVP summary for."
+			}
+		case "/checkInCode":
+			userState.State = model.StateShowCheckInCode
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleShowCheckInCode(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want the check-in code for."
+			}
+		case "/liveCheckInCode":
+			userState.State = model.StateLiveCheckInCode
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleLiveCheckInCode(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want a live check-in code for."
+			}
+		case "/setCheckInWindows":
+			userState.State = model.StateSettingCheckInWindowsEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingCheckInWindowsEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to set check-in windows for."
+			}
+		case "/stopLiveCheckInCode":
+			if stopLiveCheckInCode(chatID) {
+				text = "Live check-in code stopped."
+			} else {
+				text = "There is no live check-in code running in this chat."
+			}
+		case "/scanTickets":
+			userState.State = model.StateScanTicketsEvent
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleScanTicketsEvent(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to scan tickets for."
+			}
+		case "/checkInParticipant":
+			userState.State = model.StateManualCheckIn
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleManualCheckIn(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the participant's name or Telegram User ID in the format: EVENT_REF_CODE NAME_OR_USER_ID"
+			}
+		case "/undoCheckIn":
+			userState.State = model.StateUndoCheckIn
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleUndoCheckIn(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the participant's name or Telegram User ID in the format: EVENT_REF_CODE NAME_OR_USER_ID"
+			}
+		case "/removeParticipant":
+			userState.State = model.StateRemovingParticipant
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRemoveParticipant(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the participant's name or Telegram User ID in the format: EVENT_REF_CODE NAME_OR_USER_ID"
+			}
+		case "/ban":
+			userState.State = model.StateBanningUser
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleBanUser(ctx, update, userState)
+			} else {
+				text = "Please provide the Telegram User ID of the person to ban, optionally followed by a reason."
+			}
+		case "/unban":
+			userState.State = model.StateUnbanningUser
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleUnbanUser(ctx, update, userState)
+			} else {
+				text = "Please provide the Telegram User ID of the person to unban."
+			}
+		case "/banList":
+			text = o.handleListBannedUsers(ctx, userID)
+		case "/requireApproval":
+			userState.State = model.StateSettingApproval
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingApproval(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and whether approval is required in the format: EVENT_REF_CODE on|off"
+			}
+		case "/applications":
+			userState.State = model.StateListApplications
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleListApplications(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event whose applications you want to review."
+			}
+		case "/approve":
+			userState.State = model.StateApprovingApplication
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleApproveApplication(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the applicant's User ID in the format: EVENT_REF_CODE USER_ID"
+			}
+		case "/reject":
+			userState.State = model.StateRejectingApplication
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRejectApplication(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the applicant's User ID in the format: EVENT_REF_CODE USER_ID [REASON]"
+			}
+		case "/createInvite":
+			userState.State = model.StateCreatingInvite
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCreateInvite(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want to create an invite for."
+			}
+		case "/invites":
+			userState.State = model.StateListInvites
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleListInvites(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event whose invites you want to see."
+			}
+		case "/revokeInvite":
+			userState.State = model.StateRevokingInvite
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRevokeInvite(ctx, update, userState)
+			} else {
+				text = "Please provide the token of the invite you want to revoke. You can find it with /invites."
+			}
+		case "/inviteOnly":
+			userState.State = model.StateSettingInviteOnly
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingInviteOnly(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and whether the event is invite-only in the format: EVENT_REF_CODE on|off"
+			}
+		case "/attendanceReport":
+			userState.State = model.StateAttendanceReport
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleAttendanceReport(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want an attendance report for."
+			}
+		case "/checkInDashboard":
+			userState.State = model.StateCheckInDashboard
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCheckInDashboard(ctx, b, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event you want a check-in dashboard for."
+			}
+		case "/stopCheckInDashboard":
+			if stopCheckInDashboard(chatID) {
+				text = "Check-in dashboard stopped."
+			} else {
+				text = "There is no check-in dashboard running in this chat."
+			}
+		case "/addChecker":
+			userState.State = model.StateAddingChecker
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleAddingChecker(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the Telegram User ID of the checker in the format: EVENT_REF_CODE USER_ID\n\n" +
+					"They can find their User ID with /myid"
+			}
+		case "/removeChecker":
+			userState.State = model.StateRemovingChecker
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleRemovingChecker(ctx, update, userState)
+			} else {
+				text = "Please provide the event reference code and the Telegram User ID of the checker to remove in the format: EVENT_REF_CODE USER_ID"
+			}
+		case "/scheduledBlasts":
+			userState.State = model.StateListScheduledBlasts
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleListScheduledBlasts(ctx, update, userState)
+			} else {
+				text = "Please provide the Reference Code of the event whose scheduled blasts you want to see."
+			}
+		case "/editBlast":
+			userState.State = model.StateEditScheduledBlast
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleEditScheduledBlast(ctx, update, userState)
+			} else {
+				text = "Please provide the ID of the scheduled blast you want to edit. Use /scheduledBlasts to find it."
+			}
+		case "/cancelBlast":
+			userState.State = model.StateCancelScheduledBlast
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleCancelScheduledBlast(ctx, update, userState)
+			} else {
+				text = "Please provide the ID of the scheduled blast you want to cancel. Use /scheduledBlasts to find it."
+			}
+		case "/setTimezone":
+			userState.State = model.StateSettingTimezone
+			if arg != "" {
+				update.Message.Text = arg
+				text = o.handleSettingTimezone(ctx, update, userState)
+			} else {
+				text = fmt.Sprintf("Your current timezone is %s. Please send the new timezone name (e.g. Asia/Singapore, Europe/London).",
+					o.organiserLocation(ctx, userID))
+			}
 		default:
 			text = "I didn't understand that command. Use /start or /help."
 		}
@@ -332,29 +873,18 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 		} else {
 			// Date format is correct, store it and move to the next state
 			userState.CurrentEvent.EventDate = eventDate
-			text = "Great! Now, please send me the EDM for the event."
-
-			// Add a Cancel button
-			params = &bot.SendMessageParams{
-				ChatID: chatID,
-				Text:   text,
-				ReplyMarkup: &models.ReplyKeyboardMarkup{
-					Keyboard: [][]models.KeyboardButton{
-						{
-							{Text: "Cancel"},
-						},
-					},
-					ResizeKeyboard:  true,
-					OneTimeKeyboard: true,
-				},
-			}
-			_, err := b.SendMessage(ctx, params)
-			if err != nil {
-				log.Println("error sending message:", err)
-			}
-			userState.State = model.StateAddingEventPicture
+			o.promptEventVenue(ctx, b, chatID, userState)
 			return
 		}
+	case model.StateAddingEventVenue:
+		o.handleAddingEventVenue(ctx, b, update, userState)
+		return
+	case model.StateAddingEventVenueName:
+		o.handleAddingEventVenueName(ctx, b, update, userState)
+		return
+	case model.StateAddingEventCheckInRadius:
+		o.handleAddingEventCheckInRadius(ctx, b, update, userState)
+		return
 	case model.StateAddingEventPicture:
 		if update.Message.Text == "Cancel" {
 			text = "Event creation cancelled. What would you like to do next?"
@@ -689,37 +1219,58 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			log.Println("error sending message:", err)
 		}
 		return
+	case model.StateCloningEvent:
+		text = o.handleCloningEvent(ctx, update, userState)
+	case model.StateSelectEventTemplate:
+		o.handleSelectEventTemplate(ctx, b, update, userState)
+		return
+	case model.StateEnteringCopyName:
+		o.handleEnteringCopyName(ctx, b, update, userState)
+		return
+	case model.StateEnteringCopyDate:
+		o.handleEnteringCopyDate(ctx, b, update, userState)
+		return
+	case model.StateSavingTemplate:
+		text = o.handleSavingTemplate(ctx, update, userState)
+	case model.StateDeletingTemplate:
+		text = o.handleDeletingTemplate(ctx, update, userState)
+	case model.StateCreatingSeries:
+		text = o.handleCreatingSeries(ctx, update, userState)
+	case model.StateViewingSeriesAttendance:
+		text = o.handleSeriesAttendance(ctx, update, userState)
+	case model.StateSelectSeriesEditScope:
+		text = o.handleSelectSeriesEditScope(update, userState)
+	case model.StateAddingAgendaSession:
+		text = o.handleAddingAgendaSession(ctx, update, userState)
+	case model.StateViewingAgenda:
+		text = o.handleViewingAgenda(ctx, update, userState)
+	case model.StateRemovingAgendaSession:
+		text = o.handleRemovingAgendaSession(ctx, update, userState)
+	case model.StateViewingSessionRoster:
+		text = o.handleViewingSessionRoster(ctx, update, userState)
+	case model.StateCheckingInSession:
+		text = o.handleSessionCheckIn(ctx, update, userState)
+	case model.StateUndoingSessionCheckIn:
+		text = o.handleUndoSessionCheckIn(ctx, update, userState)
+	case model.StateSettingGuestPolicy:
+		text = o.handleSettingGuestPolicy(ctx, update, userState)
+	case model.StateSettingCapacity:
+		text = o.handleSettingCapacity(ctx, update, userState)
+	case model.StateCheckingInGuests:
+		text = o.handleCheckInGuests(ctx, update, userState)
+	case model.StateUndoingGuestCheckIn:
+		text = o.handleUndoGuestCheckIn(ctx, update, userState)
 	case model.StateDeleteEvent:
-		eventID := update.Message.Text
-
-		// Check ownership
-		isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
-		if err != nil {
-			log.Println("error checking event ownership:", err)
-			text = fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
-			userState.State = model.StateIdle
-			break
-		}
-
-		if !isOwner {
-			text = "Only the event owner can delete an event."
-			userState.State = model.StateIdle
-			break
-		}
-
-		err = o.FirebaseConnector.DeleteEvent(ctx, eventID)
-		if err != nil {
-			log.Println("error deleting event:", err)
-			text = fmt.Sprintf("Error deleting event with ID '%s'. Please check the ID and try again.", eventID)
-		} else {
-			text = fmt.Sprintf("Event with ID '%s' has been successfully deleted.", eventID)
-		}
-		userState.State = model.StateIdle
+		text = o.handleDeleteEvent(ctx, update, userState)
+	case model.StateConfirmDeleteEvent:
+		text = o.handleConfirmDeleteEvent(ctx, update, userState)
+	case model.StateRestoringEvent:
+		text = o.handleRestoringEvent(ctx, update, userState)
 	case model.StateListParticipants:
 		eventID := update.Message.Text
 
 		// Check ownership
-		isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
+		allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionViewParticipants)
 		if err != nil {
 			log.Println("error checking event ownership:", err)
 			text = fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
@@ -727,8 +1278,8 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			break
 		}
 
-		if !isOwner {
-			text = "Only the event owner can delete an event."
+		if !allowed {
+			text = "You don't have permission to list participants of this event."
 			userState.State = model.StateIdle
 			break
 		}
@@ -753,8 +1304,15 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 					eventID)
 			}
 
+			loc := o.organiserLocation(ctx, userID)
 			for i := range participants {
-				text += fmt.Sprintf("- Name: %s\n", participants[i].Name)
+				text += fmt.Sprintf("- %s\n", describeCheckInStatus(&participants[i], eventID, loc))
+			}
+			if headcount := event.Headcount(); headcount > len(event.Participants) || event.Capacity > 0 {
+				text += fmt.Sprintf("\nHeadcount with guests: %d", headcount)
+				if event.Capacity > 0 {
+					text += fmt.Sprintf(" of %d places", event.Capacity)
+				}
 			}
 		}
 		userState.State = model.StateIdle
@@ -762,7 +1320,7 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 		if userState.CurrentEvent == nil {
 			// First, check ownership of the event
 			eventID := update.Message.Text
-			isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
+			allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionMessage)
 			if err != nil {
 				log.Println("error checking event ownership:", err)
 				_, err = b.SendMessage(ctx, &bot.SendMessageParams{
@@ -776,10 +1334,10 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 				return
 			}
 
-			if !isOwner {
+			if !allowed {
 				_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 					ChatID: update.Message.Chat.ID,
-					Text:   "Only the event owner or coowners can send messages to participants.",
+					Text:   "You don't have permission to send messages to participants of this event.",
 				})
 				if err != nil {
 					log.Println("error sending message:", err)
@@ -812,103 +1370,143 @@ func (o *OrganiserBotHandler) Handler(ctx context.Context, b *bot.Bot, update *m
 			return
 		}
 
-		defer func() {
-			userState.State = model.StateIdle
-			userState.CurrentEvent = nil
-		}()
-
-		eventID := userState.CurrentEvent.ID
-		message := update.Message.Text
-
-		participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
-		if err != nil {
-			log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
-			_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   "Error retrieving participants. Please try again.",
-			})
-			if err != nil {
-				log.Println("error sending message:", err)
-			}
-			return
-		}
-
-		// Get the participant bot token from environment variable
-		participantBotToken := os.Getenv("PARTICIPANT_BOT_TOKEN")
-		if participantBotToken == "" {
-			log.Println("Participant bot token not set")
-			_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   "Error: Participant bot token not configured.",
-			})
-			if err != nil {
-				log.Println("error sending message:", err)
-			}
-			return
+		// Hold on to the message and ask whether to send it now or later
+		userState.CurrentBlast = &model.ScheduledBlast{
+			EventID:   userState.CurrentEvent.ID,
+			CreatedBy: userID,
+			Message:   update.Message.Text,
 		}
-
-		// Create a new bot instance for the participant bot
-		participantBot, err := bot.New(participantBotToken)
-		if err != nil {
-			log.Println("error creating participant bot:", err)
-			_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   "Error creating participant bot. Please try again.",
-			})
-			if err != nil {
-				log.Println("error sending message:", err)
-			}
-			return
-		}
-
-		sort.Slice(participants, func(i, j int) bool {
-			return participants[i].Name < participants[j].Name
-		})
-
-		successCount := 0
-		failureCount := 0
-
-		for _, participant := range participants {
-			var eventDetails string
-			if len(userState.CurrentEvent.EventDetails) > 0 {
-				eventDetails += "Event Details:\n"
-				for _, detail := range userState.CurrentEvent.EventDetails {
-					eventDetails += fmt.Sprintf("  - Q: %s\n", detail.Question)
-					eventDetails += fmt.Sprintf("    A: %s\n", detail.Answer)
-				}
-			}
-
-			fullMessage := fmt.Sprintf(`Message from the organiser:
-%s		
-%s
-Event Name: %s
-Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.CurrentEvent.EventDate.Format("2006-01-02"))
-
-			// Send message using the participant bot
-			_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: participant.UserID,
-				Text:   fullMessage,
-			})
-
-			if err != nil {
-				log.Printf("Error sending message to participant %d: %v", participant.UserID, err)
-				failureCount++
-			} else {
-				successCount++
-			}
+		params = &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   "Would you like to send this message now or schedule it for later?",
+			ReplyMarkup: &models.ReplyKeyboardMarkup{
+				Keyboard: [][]models.KeyboardButton{
+					{
+						{Text: "Send now"},
+						{Text: "Schedule for later"},
+					},
+					{
+						{Text: "Cancel"},
+					},
+				},
+				ResizeKeyboard:  true,
+				OneTimeKeyboard: true,
+			},
 		}
-
-		// Send summary to the organizer
-		summaryText := fmt.Sprintf("Message sent successfully to %d participants.\n%d participants could not receive the message.",
-			successCount, failureCount)
-
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   summaryText,
-		})
+		_, err := b.SendMessage(ctx, params)
 		if err != nil {
-			log.Println("error sending summary message:", err)
+			log.Println("error sending message:", err)
 		}
+		userState.State = model.StateSelectBlastTiming
+		return
+	case model.StateSelectBlastTiming:
+		text = o.handleSelectBlastTiming(ctx, b, update, userState)
+	case model.StateSchedulingBlastTime:
+		text = o.handleSchedulingBlastTime(ctx, update, userState)
+	case model.StateListScheduledBlasts:
+		text = o.handleListScheduledBlasts(ctx, update, userState)
+	case model.StateEditScheduledBlast:
+		text = o.handleEditScheduledBlast(ctx, update, userState)
+	case model.StateSelectScheduledBlastEditOption:
+		text = o.handleSelectScheduledBlastEditOption(ctx, update, userState)
+	case model.StateEditScheduledBlastMessage:
+		text = o.handleEditScheduledBlastMessage(ctx, update, userState)
+	case model.StateEditScheduledBlastTime:
+		text = o.handleEditScheduledBlastTime(ctx, update, userState)
+	case model.StateCancelScheduledBlast:
+		text = o.handleCancelScheduledBlast(ctx, update, userState)
+	case model.StateSettingTimezone:
+		text = o.handleSettingTimezone(ctx, update, userState)
+	case model.StateExportParticipants:
+		text = o.handleExportParticipants(ctx, b, update, userState)
+	case model.StateSelectExportFormat:
+		text = o.handleSelectExportFormat(ctx, b, update, userState)
+	case model.StateRSVPSummary:
+		text = o.handleRSVPSummary(ctx, update, userState)
+	case model.StateScanTicketsEvent:
+		text = o.handleScanTicketsEvent(ctx, update, userState)
+	case model.StateScanningTickets:
+		text = o.handleScanningTickets(ctx, update, userState)
+	case model.StateAddingChecker:
+		text = o.handleAddingChecker(ctx, update, userState)
+	case model.StateRemovingChecker:
+		text = o.handleRemovingChecker(ctx, update, userState)
+	case model.StateSettingCheckInCodePeriod:
+		text = o.handleSettingCheckInCodePeriod(ctx, update, userState)
+	case model.StateShowCheckInCode:
+		text = o.handleShowCheckInCode(ctx, update, userState)
+	case model.StateLiveCheckInCode:
+		text = o.handleLiveCheckInCode(ctx, b, update, userState)
+	case model.StateSettingCheckInWindowsEvent:
+		text = o.handleSettingCheckInWindowsEvent(ctx, update, userState)
+	case model.StateEnteringCheckInWindows:
+		text = o.handleEnteringCheckInWindows(ctx, update, userState)
+	case model.StateAttendanceReport:
+		text = o.handleAttendanceReport(ctx, b, update, userState)
+	case model.StateCheckInDashboard:
+		text = o.handleCheckInDashboard(ctx, b, update, userState)
+	case model.StateManualCheckIn:
+		text = o.handleManualCheckIn(ctx, update, userState)
+	case model.StateSelectManualCheckInMatch:
+		text = o.handleSelectManualCheckInMatch(ctx, update, userState, false)
+	case model.StateUndoCheckIn:
+		text = o.handleUndoCheckIn(ctx, update, userState)
+	case model.StateSelectUndoCheckInMatch:
+		text = o.handleSelectManualCheckInMatch(ctx, update, userState, true)
+	case model.StateRemovingParticipant:
+		text = o.handleRemoveParticipant(ctx, update, userState)
+	case model.StateSelectRemoveParticipantMatch:
+		text = o.handleSelectRemoveParticipantMatch(ctx, update, userState)
+	case model.StateConfirmRemoveParticipant:
+		text = o.handleConfirmRemoveParticipant(ctx, update, userState)
+	case model.StateBanningUser:
+		text = o.handleBanUser(ctx, update, userState)
+	case model.StateUnbanningUser:
+		text = o.handleUnbanUser(ctx, update, userState)
+	case model.StateSettingApproval:
+		text = o.handleSettingApproval(ctx, update, userState)
+	case model.StateListApplications:
+		text = o.handleListApplications(ctx, update, userState)
+	case model.StateApprovingApplication:
+		text = o.handleApproveApplication(ctx, update, userState)
+	case model.StateRejectingApplication:
+		text = o.handleRejectApplication(ctx, update, userState)
+	case model.StateSettingCoownerRole:
+		text = o.handleSettingCoownerRole(ctx, update, userState)
+	case model.StateInvitingCoowner:
+		text = o.handleInvitingCoowner(ctx, b, update, userState)
+	case model.StateListingCoowners:
+		text = o.handleListCoowners(ctx, update, userState)
+	case model.StatePublishingEvent:
+		text = o.handlePublishingEvent(ctx, update, userState)
+	case model.StateCancellingEvent:
+		text = o.handleCancellingEvent(ctx, update, userState)
+	case model.StateConfirmCancelEvent:
+		text = o.handleConfirmCancelEvent(ctx, update, userState)
+	case model.StateCompletingEvent:
+		text = o.handleCompletingEvent(ctx, update, userState)
+	case model.StateConfirmChangeNotice:
+		text = o.handleConfirmChangeNotice(ctx, update.Message.Text, userState)
+	case model.StateTransferringOwnership:
+		text = o.handleTransferringOwnership(ctx, b, update, userState)
+	case model.StateAcceptingOwnership:
+		text = o.handleAcceptingOwnership(ctx, b, update, userState)
+	case model.StateDecliningOwnership:
+		text = o.handleDecliningOwnership(ctx, b, update, userState)
+	case model.StateCreatingInvite:
+		text = o.handleCreateInvite(ctx, update, userState)
+	case model.StateEnteringInviteLabel:
+		text = o.handleEnteringInviteLabel(ctx, update, userState)
+	case model.StateEnteringInviteMaxUses:
+		text = o.handleEnteringInviteMaxUses(ctx, update, userState)
+	case model.StateEnteringInviteExpiry:
+		text = o.handleEnteringInviteExpiry(ctx, update, userState)
+	case model.StateListInvites:
+		text = o.handleListInvites(ctx, update, userState)
+	case model.StateRevokingInvite:
+		text = o.handleRevokeInvite(ctx, update, userState)
+	case model.StateSettingInviteOnly:
+		text = o.handleSettingInviteOnly(ctx, update, userState)
 
 	// RSVP Handling
 	case model.StateAddingRSVPQuestion:
@@ -980,7 +1578,9 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			"1. Yes/No (binary choice)\n" +
 			"2. Multiple Choice (select one option)\n" +
 			"3. Multiple Select (select multiple options)\n" +
-			"4. Short Answer (free text)"
+			"4. Short Answer (free text)\n" +
+			"5. Rating (1-5)\n" +
+			"6. Number"
 		userState.State = model.StateSelectingRSVPType
 
 	case model.StateSelectingRSVPType:
@@ -1009,8 +1609,19 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			userState.CurrentRSVPQuestion.Type = questionType
 			text = "Would you like to add an image to this question? (yes/no)"
 			userState.State = model.StateAddingRSVPImage
+		case "5":
+			questionType = model.QuestionTypeRating
+			userState.CurrentRSVPQuestion.Type = questionType
+			userState.CurrentRSVPQuestion.Options = []string{"1", "2", "3", "4", "5"}
+			text = "Would you like to add an image to this question? (yes/no)"
+			userState.State = model.StateAddingRSVPImage
+		case "6":
+			questionType = model.QuestionTypeNumber
+			userState.CurrentRSVPQuestion.Type = questionType
+			text = "Would you like to add an image to this question? (yes/no)"
+			userState.State = model.StateAddingRSVPImage
 		default:
-			text = "Invalid option. Please enter a number between 1 and 4."
+			text = "Invalid option. Please enter a number between 1 and 6."
 		}
 
 	case model.StateAddingRSVPOptions:
@@ -1062,33 +1673,55 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		}
 	case model.StateSettingEventCheckInCode:
 		eventID := update.Message.Text
+
+		// Check ownership
+		allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionCheckIn)
+		if err != nil {
+			log.Println("error checking event ownership:", err)
+			text = fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+			userState.State = model.StateIdle
+			break
+		}
+
+		if !allowed {
+			text = "You don't have permission to set the check-in code for this event."
+			userState.State = model.StateIdle
+			break
+		}
+
 		event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
 		if err != nil {
 			log.Println("error reading event:", err)
 			text = fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
 			userState.State = model.StateIdle
 		} else {
+			event.ID = eventID
 			userState.CurrentEvent = event
 
 			// Show current check-in code if it exists
-			currentCode := "not set"
-			if event.CheckInCode != "" {
-				currentCode = event.CheckInCode
-			}
-
-			text = fmt.Sprintf("Current check-in code for event '%s' is: %s\n\nPlease enter a new 4-digit check-in code for this event:", event.Name, currentCode)
+			text = fmt.Sprintf("Current check-in code for event '%s' is: %s\n\n"+
+				"Please enter a new 4-digit check-in code for this event, or type 'rotating' to use a code that changes automatically:",
+				event.Name, describeCheckInCode(event))
 			userState.State = model.StateUpdatingEventCheckInCode
 		}
 	case model.StateUpdatingEventCheckInCode:
-		// Validate that the input is a 4-digit code
 		code := update.Message.Text
+		if strings.ToLower(code) == "rotating" {
+			text = fmt.Sprintf("How many seconds should each code be valid for? (%d-%d, e.g., 60)", minCheckInCodePeriod, maxCheckInCodePeriod)
+			userState.State = model.StateSettingCheckInCodePeriod
+			break
+		}
+
+		// Validate that the input is a 4-digit code
 		if len(code) != 4 || !isNumeric(code) {
-			text = "Please enter a valid 4-digit numeric code (e.g., 1234)."
+			text = "Please enter a valid 4-digit numeric code (e.g., 1234) or 'rotating'."
 			break
 		}
 
-		// Update the event with the new check-in code
+		// Update the event with the new check-in code, switching off any rotating code
 		userState.CurrentEvent.CheckInCode = code
+		userState.CurrentEvent.CheckInCodeSecret = ""
+		userState.CurrentEvent.CheckInCodePeriod = 0
 		err := o.FirebaseConnector.UpdateEvent(ctx, userState.CurrentEvent.ID, *userState.CurrentEvent)
 		if err != nil {
 			log.Println("error updating event:", err)
@@ -1102,12 +1735,24 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 	case model.StateAddingCoowner:
 		// Split the input into event ID and user ID
 		parts := strings.Split(update.Message.Text, " ")
-		if len(parts) != 2 {
-			text = "Invalid format. Please use: EVENT_REFERENCE_CODE USER_ID"
+		if len(parts) != 2 && len(parts) != 3 {
+			text = "Invalid format. Please use: EVENT_REFERENCE_CODE USER_ID [ROLE]"
 			userState.State = model.StateIdle
 			break
 		}
 
+		// Coowners are admins unless another role is given
+		role := model.RoleAdmin
+		if len(parts) == 3 {
+			var ok bool
+			role, ok = model.ParseCoownerRole(strings.ToLower(parts[2]))
+			if !ok {
+				text = "Unknown role. Please choose one of:\n" + describeCoownerRoles()
+				userState.State = model.StateIdle
+				break
+			}
+		}
+
 		eventID := parts[0]
 		coownerUserID, err := strconv.ParseInt(parts[1], 10, 64)
 		if err != nil {
@@ -1117,25 +1762,25 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		}
 
 		// Check if the user is the event owner
-		isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
+		allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionManageTeam)
 		if err != nil {
 			text = "Error checking event ownership. Please try again."
 			userState.State = model.StateIdle
 			break
 		}
 
-		if !isOwner {
-			text = "Only the event owner can add coowners."
+		if !allowed {
+			text = "You don't have permission to add coowners to this event."
 			userState.State = model.StateIdle
 			break
 		}
 
 		// Add the coowner
-		err = o.FirebaseConnector.AddCoowner(ctx, eventID, userID, coownerUserID)
+		err = o.FirebaseConnector.AddCoowner(ctx, eventID, coownerUserID, role)
 		if err != nil {
 			text = fmt.Sprintf("Error adding coowner: %v", err)
 		} else {
-			text = fmt.Sprintf("Coowner with Telegram ID %d added successfully to event %s", coownerUserID, eventID)
+			text = fmt.Sprintf("Coowner with Telegram ID %d added successfully to event %s as %s. Use /setRole to change their role.", coownerUserID, eventID, role)
 		}
 		userState.State = model.StateIdle
 	// New state for editing event
@@ -1143,7 +1788,7 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		eventID := update.Message.Text
 
 		// Check ownership
-		isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
+		allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionEditEvent)
 		if err != nil {
 			log.Println("error checking event ownership:", err)
 			text = fmt.Sprintf("Error checking ownership for event with ID '%s'. Please try again.", eventID)
@@ -1151,12 +1796,6 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			break
 		}
 
-		if !isOwner {
-			text = "Only the event owner or coowners can edit an event."
-			userState.State = model.StateIdle
-			break
-		}
-
 		// Retrieve the event
 		event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
 		if err != nil {
@@ -1166,14 +1805,30 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			break
 		}
 
+		if !allowed {
+			text = "You don't have permission to edit this event."
+			if event.CurrentStatus() == model.EventStatusCompleted {
+				text = fmt.Sprintf("'%s' is completed and can no longer be edited.", event.Name)
+			}
+			userState.State = model.StateIdle
+			break
+		}
+
 		userState.CurrentEvent = event
+		userState.TempOptions = nil
+
+		// Sessions of a series can be edited alone or together with the later ones
+		if event.SeriesID != "" {
+			text = fmt.Sprintf("'%s' is session %d of a series. What do you want to edit?\n"+
+				"1. This session only\n"+
+				"2. This and all later sessions\n"+
+				"3. Cancel Edit", event.Name, event.SeriesIndex)
+			userState.State = model.StateSelectSeriesEditScope
+			break
+		}
 
 		// Provide editing options
-		text = fmt.Sprintf("Editing event '%s'. Choose what you want to edit:\n"+
-			"1. Event Name\n"+
-			"2. Event Date\n"+
-			"3. Event Details\n"+
-			"4. Cancel Edit", event.Name)
+		text = editEventOptionsText(event)
 		userState.State = model.StateSelectEditOption
 
 	case model.StateSelectEditOption:
@@ -1183,6 +1838,10 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			userState.State = model.StateEditEventName
 		case "2":
 			text = "Enter the new event date (YYYY-MM-DD):"
+			if editsWholeSeries(userState) {
+				text = "Dates are changed one session at a time, so only this session will move.\n" + text
+				userState.TempOptions = nil
+			}
 			userState.State = model.StateEditEventDate
 		case "3":
 			text = "Current event details:\n"
@@ -1192,23 +1851,38 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			text += "\nEnter the number of the detail you want to edit:"
 			userState.State = model.StateEditEventDetails
 		case "4":
+			// Remember the venue as participants know it so a change can be described to them
+			userState.LastQuestion = describeVenueForChange(userState.CurrentEvent.Venue)
+			text = describeVenue(userState.CurrentEvent) + "\n\n" +
+				"Send a new Telegram venue or location (📎 → Location), or type the venue name with the address on the next line.\n" +
+				"Send 'keep' to only change the check-in radius, or 'remove' to clear the venue."
+			userState.State = model.StateEditEventVenue
+		case "5":
 			text = "Event editing cancelled."
 			userState.State = model.StateIdle
 			userState.CurrentEvent = nil
+			userState.TempOptions = nil
 		default:
-			text = "Invalid option. Please choose 1-4."
+			text = "Invalid option. Please choose 1-5."
 		}
 
 	case model.StateEditEventName:
+		oldName := userState.CurrentEvent.Name
 		userState.CurrentEvent.Name = update.Message.Text
-		text = "Event name updated. Saving changes."
 		err := o.FirebaseConnector.UpdateEvent(ctx, userState.CurrentEvent.ID, *userState.CurrentEvent)
 		if err != nil {
 			log.Println("error updating event:", err)
 			text = "Error updating event name. Please try again."
+			userState.State = model.StateIdle
+			userState.CurrentEvent = nil
+			break
 		}
-		userState.State = model.StateIdle
-		userState.CurrentEvent = nil
+		newName := userState.CurrentEvent.Name
+		seriesText, occurrences := o.applyToLaterOccurrences(ctx, userState, func(event *model.Event) {
+			event.Name = newName
+		})
+		text = o.offerChangeNotice(userState, userState.CurrentEvent, occurrences, "Event name updated."+seriesText,
+			describeEventChange("Name", oldName, newName))
 
 	case model.StateEditEventDate:
 		// Validate the date format
@@ -1218,15 +1892,25 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 			break
 		}
 
+		oldDate := userState.CurrentEvent.EventDate
 		userState.CurrentEvent.EventDate = eventDate
-		text = "Event date updated. Saving changes."
 		err = o.FirebaseConnector.UpdateEvent(ctx, userState.CurrentEvent.ID, *userState.CurrentEvent)
 		if err != nil {
 			log.Println("error updating event:", err)
 			text = "Error updating event date. Please try again."
+			userState.State = model.StateIdle
+			userState.CurrentEvent = nil
+			break
 		}
-		userState.State = model.StateIdle
-		userState.CurrentEvent = nil
+		text = o.offerChangeNotice(userState, userState.CurrentEvent, nil, "Event date updated.",
+			describeEventChange("Date", oldDate.Format("2006-01-02"), eventDate.Format("2006-01-02")))
+
+	case model.StateEditEventVenue:
+		text = o.handleEditEventVenue(ctx, update, userState)
+	case model.StateEditEventVenueName:
+		text = o.handleEditEventVenueName(ctx, update, userState)
+	case model.StateEditEventCheckInRadius:
+		text = o.handleEditEventCheckInRadius(ctx, update, userState)
 
 	case model.StateEditEventDetails:
 		// Try to parse the index of the detail to edit
@@ -1250,16 +1934,29 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		index, _ := strconv.Atoi(userState.LastQuestion)
 
 		// Update the answer
-		userState.CurrentEvent.EventDetails[index].Answer = update.Message.Text
+		detail := &userState.CurrentEvent.EventDetails[index]
+		oldAnswer := detail.Answer
+		detail.Answer = update.Message.Text
 
-		text = "Event detail updated. Saving changes."
 		err := o.FirebaseConnector.UpdateEvent(ctx, userState.CurrentEvent.ID, *userState.CurrentEvent)
 		if err != nil {
 			log.Println("error updating event:", err)
 			text = "Error updating event detail. Please try again."
+			userState.State = model.StateIdle
+			userState.CurrentEvent = nil
+			userState.LastQuestion = ""
+			break
 		}
-		userState.State = model.StateIdle
-		userState.CurrentEvent = nil
+		question, answer := detail.Question, detail.Answer
+		seriesText, occurrences := o.applyToLaterOccurrences(ctx, userState, func(event *model.Event) {
+			for i := range event.EventDetails {
+				if event.EventDetails[i].Question == question {
+					event.EventDetails[i].Answer = answer
+				}
+			}
+		})
+		text = o.offerChangeNotice(userState, userState.CurrentEvent, occurrences, "Event detail updated."+seriesText,
+			describeEventChange(question, oldAnswer, answer))
 	case model.StateRemovingCoowner:
 		// Split the input into event ID and user ID
 		parts := strings.Split(update.Message.Text, " ")
@@ -1279,15 +1976,15 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		}
 
 		// Check ownership
-		isOwner, err := o.FirebaseConnector.IsEventOwner(ctx, eventID, userID)
+		allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, userID, model.PermissionManageTeam)
 		if err != nil {
 			text = "Error checking event ownership. Please try again."
 			userState.State = model.StateIdle
 			break
 		}
 
-		if !isOwner {
-			text = "Only the event owner can remove coowners."
+		if !allowed {
+			text = "You don't have permission to remove coowners from this event."
 			userState.State = model.StateIdle
 			break
 		}
@@ -1316,7 +2013,7 @@ Event Date: %s`, message, eventDetails, userState.CurrentEvent.Name, userState.C
 		}
 
 		// Remove the coowner
-		err = o.FirebaseConnector.RemoveCoowner(ctx, eventID, userID, coownerUserID)
+		err = o.FirebaseConnector.RemoveCoowner(ctx, eventID, coownerUserID)
 		if err != nil {
 			text = fmt.Sprintf("Error removing coowner: %v", err)
 		} else {
@@ -1379,6 +2076,10 @@ func (o *OrganiserBotHandler) saveEvent(ctx context.Context, event *model.Event)
 		}
 	}
 
+	// New events stay drafts until they are published
+	event.Status = model.EventStatusDraft
+	event.StatusChangedAt = time.Now().UTC()
+
 	// Create the event in Firestore
 	refKey, err := o.FirebaseConnector.CreateEvent(ctx, *event)
 	if err != nil {
@@ -1406,6 +2107,10 @@ func getRSVPTypeString(qType model.QuestionType) string {
 		return "Multiple Select"
 	case model.QuestionTypeShortAnswer:
 		return "Short Answer"
+	case model.QuestionTypeRating:
+		return "Rating (1-5)"
+	case model.QuestionTypeNumber:
+		return "Number"
 	default:
 		return "Unknown"
 	}
@@ -1423,10 +2128,10 @@ func (o *OrganiserBotHandler) sendEventCreationConfirmation(ctx context.Context,
 	// Create success message text
 	var successText string
 	if hasRSVP {
-		successText = fmt.Sprintf("Event '%s' created successfully with %d RSVP questions!",
+		successText = fmt.Sprintf("Event '%s' saved as a draft with %d RSVP questions!",
 			event.Name, len(event.RSVPQuestions))
 	} else {
-		successText = fmt.Sprintf("Event '%s' created successfully without RSVP questions!",
+		successText = fmt.Sprintf("Event '%s' saved as a draft without RSVP questions!",
 			event.Name)
 	}
 
@@ -1442,19 +2147,10 @@ func (o *OrganiserBotHandler) sendEventCreationConfirmation(ctx context.Context,
 		return err
 	}
 
-	// Get participant bot username from environment variable
-	participantBotUsername := os.Getenv("PARTICIPANT_BOT_NAME")
-	if participantBotUsername == "" {
-		participantBotUsername = "your_participant_bot" // Fallback if not set
-	}
-
-	// Create deep link for participants
-	deepLink := fmt.Sprintf("https://t.me/%s?start=join_%s", participantBotUsername, refKey)
-
-	// Follow up with the reference code and join link as a separate message to make it copyable
+	// Follow up with the reference code as a separate message to make it copyable; the join link comes with /publishEvent
 	codeMsg := &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      fmt.Sprintf("Reference Code: <code>%s</code>\n\nParticipants can join using this link:\n%s", refKey, deepLink),
+		Text:      fmt.Sprintf("Reference Code: <code>%s</code>\n\nParticipants can't join yet. When the event is ready, send /publishEvent %s to get the join link.", refKey, refKey),
 		ParseMode: "HTML", // Using HTML parsing mode to enable code formatting
 	}
 	_, err = b.SendMessage(ctx, codeMsg)
@@ -1476,6 +2172,12 @@ func (o *OrganiserBotHandler) sendEventCreationConfirmation(ctx context.Context,
 	return nil
 }
 
+// splitCommand splits a message into its leading command and the remaining argument text
+func splitCommand(text string) (string, string) {
+	command, arg, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return command, strings.TrimSpace(arg)
+}
+
 func isNumeric(s string) bool {
 	for _, r := range s {
 		if r < '0' || r > '9' {
@@ -1499,16 +2201,88 @@ func getOrganizerMainMenuKeyboard() *models.ReplyKeyboardMarkup {
 			{
 				{Text: "/blast"},
 				{Text: "/deleteEvent"},
+				{Text: "/restoreEvent"},
 			},
 			{
 				{Text: "/editEvent"},
 				{Text: "/addCoowner"},
 			},
+			{
+				{Text: "/cloneEvent"},
+				{Text: "/templates"},
+			},
 			{
 				{Text: "/removeCoowner"},
-				{Text: "/myid"},
+				{Text: "/setRole"},
+			},
+			{
+				{Text: "/scheduledBlasts"},
+				{Text: "/setTimezone"},
+			},
+			{
+				{Text: "/exportParticipants"},
+				{Text: "/rsvpSummary"},
+			},
+			{
+				{Text: "/scanTickets"},
+				{Text: "/setCheckInWindows"},
+			},
+			{
+				{Text: "/checkInParticipant"},
+				{Text: "/undoCheckIn"},
+			},
+			{
+				{Text: "/checkInDashboard"},
+				{Text: "/attendanceReport"},
+			},
+			{
+				{Text: "/removeParticipant"},
+				{Text: "/banList"},
 			},
 			{
+				{Text: "/requireApproval"},
+				{Text: "/applications"},
+			},
+			{
+				{Text: "/createInvite"},
+				{Text: "/invites"},
+			},
+			{
+				{Text: "/inviteCoowner"},
+				{Text: "/listCoowners"},
+			},
+			{
+				{Text: "/transferOwnership"},
+				{Text: "/pastEvents"},
+			},
+			{
+				{Text: "/publishEvent"},
+				{Text: "/cancelEvent"},
+				{Text: "/completeEvent"},
+			},
+			{
+				{Text: "/createSeries"},
+				{Text: "/seriesAttendance"},
+			},
+			{
+				{Text: "/addSession"},
+				{Text: "/agenda"},
+				{Text: "/sessionRoster"},
+			},
+			{
+				{Text: "/checkInSession"},
+				{Text: "/undoSessionCheckIn"},
+			},
+			{
+				{Text: "/setGuests"},
+				{Text: "/setCapacity"},
+			},
+			{
+				{Text: "/checkInGuests"},
+				{Text: "/undoGuestCheckIn"},
+			},
+			{
+				{Text: "/myid"},
 				{Text: "/help"},
 			},
 		},
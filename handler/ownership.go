@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func (o *OrganiserBotHandler) handleTransferringOwnership(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	input := strings.TrimSpace(update.Message.Text)
+	if eventID, ok := strings.CutSuffix(input, " cancel"); ok {
+		return o.withdrawOwnershipTransfer(ctx, b, update, strings.TrimSpace(eventID))
+	}
+
+	eventID, nomineeID, errText := parseEventAndUserID(input)
+	if errText != "" {
+		return errText
+	}
+
+	event, err := o.FirebaseConnector.NominateOwner(ctx, eventID, update.Message.From.ID, nomineeID)
+	switch {
+	case errors.Is(err, model.ErrNotPrimaryOwner):
+		return "Only the event's primary owner can transfer ownership."
+	case errors.Is(err, model.ErrNotCoowner):
+		return fmt.Sprintf("User %d is not a coowner of this event. Add them with /addCoowner or /inviteCoowner first.", nomineeID)
+	case err != nil:
+		log.Println("error nominating owner:", err)
+		return fmt.Sprintf("Error starting the transfer for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: nomineeID,
+		Text: fmt.Sprintf("👑 You have been asked to become the owner of '%s' (Reference Code: %s).\n"+
+			"Accept: /acceptOwnership %s\nDecline: /declineOwnership %s", event.Name, eventID, eventID, eventID),
+	})
+	if err != nil {
+		log.Printf("error notifying nominee %d: %v", nomineeID, err)
+		return fmt.Sprintf("Transfer of '%s' started, but user %d could not be notified. Ask them to send /acceptOwnership %s.", event.Name, nomineeID, eventID)
+	}
+	return fmt.Sprintf("User %d has been asked to take over '%s'. You stay owner until they accept.\nTo withdraw, send /transferOwnership %s cancel.", nomineeID, event.Name, eventID)
+}
+
+// withdrawOwnershipTransfer clears the owner's pending nomination and lets the nominee know
+func (o *OrganiserBotHandler) withdrawOwnershipTransfer(ctx context.Context, b *bot.Bot, update *models.Update, eventID string) string {
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	nomineeID := event.PendingOwner
+	if nomineeID == 0 {
+		return fmt.Sprintf("No ownership transfer is pending for '%s'.", event.Name)
+	}
+
+	_, err = o.FirebaseConnector.NominateOwner(ctx, eventID, update.Message.From.ID, 0)
+	if errors.Is(err, model.ErrNotPrimaryOwner) {
+		return "Only the event's primary owner can transfer ownership."
+	} else if err != nil {
+		log.Println("error withdrawing ownership transfer:", err)
+		return "Error withdrawing the transfer. Please try again."
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: nomineeID,
+		Text:   fmt.Sprintf("The request to take over '%s' has been withdrawn.", event.Name),
+	})
+	if err != nil {
+		log.Printf("error notifying nominee %d: %v", nomineeID, err)
+	}
+	return fmt.Sprintf("The ownership transfer of '%s' has been withdrawn.", event.Name)
+}
+
+func (o *OrganiserBotHandler) handleAcceptingOwnership(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+	nomineeID := update.Message.From.ID
+
+	previous, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	previousOwnerID := previous.UserID
+
+	event, err := o.FirebaseConnector.AcceptOwnership(ctx, eventID, nomineeID)
+	switch {
+	case errors.Is(err, model.ErrNoTransferPending):
+		return fmt.Sprintf("You have not been asked to take over '%s', or the request was withdrawn.", previous.Name)
+	case errors.Is(err, model.ErrNotCoowner):
+		return fmt.Sprintf("You are no longer a coowner of '%s', so the transfer cannot go ahead.", previous.Name)
+	case err != nil:
+		log.Println("error transferring ownership:", err)
+		return "Error transferring ownership. Please try again."
+	}
+
+	err = o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      eventID,
+		Action:       model.AuditActionOwnerChanged,
+		ActorID:      nomineeID,
+		TargetUserID: previousOwnerID,
+		Details:      fmt.Sprintf("Ownership transferred from %d to %d", previousOwnerID, nomineeID),
+		At:           time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: previousOwnerID,
+		Text:   fmt.Sprintf("👑 User %d has accepted ownership of '%s'. You remain on the team as %s.", nomineeID, event.Name, model.RoleAdmin),
+	})
+	if err != nil {
+		log.Printf("error notifying previous owner %d: %v", previousOwnerID, err)
+	}
+	return fmt.Sprintf("You are now the owner of '%s'. The previous owner (User %d) stays on the team as %s.", event.Name, previousOwnerID, model.RoleAdmin)
+}
+
+func (o *OrganiserBotHandler) handleDecliningOwnership(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+	nomineeID := update.Message.From.ID
+
+	event, err := o.FirebaseConnector.DeclineOwnership(ctx, eventID, nomineeID)
+	if errors.Is(err, model.ErrNoTransferPending) {
+		return "You have not been asked to take over this event, or the request was withdrawn."
+	} else if err != nil {
+		log.Println("error declining ownership:", err)
+		return fmt.Sprintf("Error declining the transfer for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: event.UserID,
+		Text:   fmt.Sprintf("User %d has declined to take over '%s'. You are still its owner.", nomineeID, event.Name),
+	})
+	if err != nil {
+		log.Printf("error notifying owner %d: %v", event.UserID, err)
+	}
+	return fmt.Sprintf("You have declined to take over '%s'.", event.Name)
+}
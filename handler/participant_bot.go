@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -69,9 +70,17 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 				if strings.HasPrefix(param, "join_") {
 					eventID := strings.TrimPrefix(param, "join_")
 
+					// Invite links carry an invite token in place of the event ID
+					invite, err := p.FirebaseConnector.ReadInvite(ctx, eventID)
+					if err == nil {
+						eventID = invite.EventID
+					} else if !errors.Is(err, model.ErrInviteDoesNotExist) {
+						log.Println("error reading invite:", err)
+					}
+
 					userState.State = model.StateJoinEvent
 					p.update.Message.Text = eventID
-					p.handleJoinEvent(ctx)
+					p.handleJoinEvent(ctx, invite)
 					return
 				}
 			}
@@ -88,6 +97,9 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 	Join an event: /joinEvent
 	Keep track of your own notes and reminders for each event: /notes
 	Check in to an event: /checkIn
+	Get your QR ticket for an event: /myTicket
+	Pick sessions from an event's agenda: /agenda
+	Register guests who come with you: /guests
 
 	Easily check-in at events using a simple code
 	Access useful event details and FAQs
@@ -116,6 +128,9 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 	/help – Get a reminder of commands and how to use me.
 	/notes - Add or view personal notes for an event.
 	/checkIn - Check in to an event.
+	/myTicket - Get your QR ticket for an event.
+	/agenda - Browse an event's agenda and pick the sessions you want to attend.
+	/guests - Register or change the guests coming with you to an event.
 	`
 
 			params = &bot.SendMessageParams{
@@ -203,6 +218,71 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 			userState.State = model.StateCheckIn
 			return
 
+		case update.Message.Text == "/myTicket":
+			text = "Please provide the Event Reference Code of the event you want your ticket for."
+
+			params = &bot.SendMessageParams{
+				ChatID: chatID,
+				Text:   text,
+				ReplyMarkup: &models.ReplyKeyboardMarkup{
+					Keyboard: [][]models.KeyboardButton{
+						{
+							{Text: "Cancel"},
+						},
+					},
+					ResizeKeyboard:  true,
+					OneTimeKeyboard: true,
+				},
+			}
+			_, err := b.SendMessage(ctx, params)
+			if err != nil {
+				log.Println("error sending message:", err)
+			}
+			userState.State = model.StateRequestingTicket
+			return
+
+		case update.Message.Text == "/agenda":
+			params = &bot.SendMessageParams{
+				ChatID: chatID,
+				Text:   "Please provide the Event Reference Code of the event whose agenda you want to see.",
+				ReplyMarkup: &models.ReplyKeyboardMarkup{
+					Keyboard: [][]models.KeyboardButton{
+						{
+							{Text: "Cancel"},
+						},
+					},
+					ResizeKeyboard:  true,
+					OneTimeKeyboard: true,
+				},
+			}
+			_, err := b.SendMessage(ctx, params)
+			if err != nil {
+				log.Println("error sending message:", err)
+			}
+			userState.State = model.StateSelectAgendaEvent
+			return
+
+		case update.Message.Text == "/guests":
+			params = &bot.SendMessageParams{
+				ChatID: chatID,
+				Text:   "Please provide the Event Reference Code of the event you are bringing guests to.",
+				ReplyMarkup: &models.ReplyKeyboardMarkup{
+					Keyboard: [][]models.KeyboardButton{
+						{
+							{Text: "Cancel"},
+						},
+					},
+					ResizeKeyboard:  true,
+					OneTimeKeyboard: true,
+				},
+			}
+			_, err := b.SendMessage(ctx, params)
+			if err != nil {
+				log.Println("error sending message:", err)
+			}
+			userState.State = model.StateSelectGuestsEvent
+			return
+
 		// Add a case to handle the "Cancel" button in various states
 		case update.Message.Text == "Cancel":
 			text = "Operation cancelled. What would you like to do next?"
@@ -225,7 +305,38 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 			text = "I didn't understand that command. Use /start or /help."
 		}
 	case model.StateJoinEvent:
-		p.handleJoinEvent(ctx)
+		p.handleJoinEvent(ctx, nil)
+		return
+	case model.StateJoiningSeries:
+		p.handleJoiningSeries(ctx, userState)
+		return
+	case model.StateSelectAgendaEvent:
+		if update.Message.Text == "Cancel" {
+			userState.State = model.StateIdle
+			text = "Operation cancelled. What would you like to do next?"
+			break
+		}
+		p.handleSelectAgendaEvent(ctx, userState)
+		return
+	case model.StateBrowsingAgenda:
+		p.handleBrowsingAgenda(ctx, userState)
+		return
+	case model.StateSelectGuestsEvent:
+		if update.Message.Text == "Cancel" {
+			userState.State = model.StateIdle
+			text = "Operation cancelled. What would you like to do next?"
+			break
+		}
+		p.handleSelectGuestsEvent(ctx, userState)
+		return
+	case model.StateEnteringGuestCount:
+		p.handleGuestCount(ctx, userState)
+		return
+	case model.StateEnteringGuestName:
+		p.handleGuestName(ctx, userState)
+		return
+	case model.StateAnsweringGuestRSVP:
+		p.handleGuestRSVPAnswer(ctx, userState)
 		return
 	case model.StatePersonalNotes:
 		if userState.CurrentEvent == nil {
@@ -234,9 +345,20 @@ func (p *ParticipantBotHandler) Handler(ctx context.Context, b *bot.Bot, update
 			p.handlePersonalNotesReply(ctx)
 		}
 		return
-	case model.StateCheckIn:
+	case model.StateCheckIn, model.StateEnteringCheckInCode:
 		p.handleCheckIn(ctx)
 		return
+	case model.StateSharingCheckInLocation:
+		p.handleCheckInLocation(ctx)
+		return
+	case model.StateRequestingTicket:
+		if update.Message.Text == "Cancel" {
+			userState.State = model.StateIdle
+			text = "Operation cancelled. What would you like to do next?"
+			break
+		}
+		p.handleRequestTicket(ctx)
+		return
 	case model.StateSelectEventForRSVP:
 		// Handle selecting which event to complete RSVP for from events list
 		if update.Message.Text == "0" {
@@ -405,7 +527,13 @@ func downloadFile(url string, filepath string) error {
 }
 func (p *ParticipantBotHandler) askRSVPQuestion(ctx context.Context, userState *model.UserState) {
 	// Check if we've gone through all questions
+	guestName := currentGuestName(userState)
 	if userState.RSVPQuestionIndex >= len(userState.CurrentEvent.RSVPQuestions) {
+		if userState.State == model.StateAnsweringGuestRSVP {
+			p.nextGuestRSVP(ctx, userState)
+			return
+		}
+
 		// Save the participant's answers
 		p.saveRSVPAnswers(ctx, userState)
 		return
@@ -419,6 +547,9 @@ func (p *ParticipantBotHandler) askRSVPQuestion(ctx context.Context, userState *
 		userState.RSVPQuestionIndex+1,
 		len(userState.CurrentEvent.RSVPQuestions),
 		question.Question)
+	if guestName != "" {
+		text = fmt.Sprintf("For %s – %s", guestName, text)
+	}
 
 	// Create the appropriate reply markup based on question type
 	var replyMarkup interface{}
@@ -438,6 +569,19 @@ func (p *ParticipantBotHandler) askRSVPQuestion(ctx context.Context, userState *
 			ResizeKeyboard:  true,
 		}
 
+	case model.QuestionTypeRating:
+		// Create a single-row keyboard with the rating scale
+		var row []models.KeyboardButton
+		for _, option := range question.Options {
+			row = append(row, models.KeyboardButton{Text: option})
+		}
+		text += "\nRate from 1 (lowest) to 5 (highest)."
+		replyMarkup = &models.ReplyKeyboardMarkup{
+			Keyboard:        [][]models.KeyboardButton{row},
+			OneTimeKeyboard: true,
+			ResizeKeyboard:  true,
+		}
+
 	case model.QuestionTypeMCQ:
 		// Create a keyboard with MCQ options
 		var rows [][]models.KeyboardButton
@@ -460,6 +604,9 @@ func (p *ParticipantBotHandler) askRSVPQuestion(ctx context.Context, userState *
 	case model.QuestionTypeShortAnswer:
 		// For short answer, just prompt for free text
 		text += "\nPlease provide your answer as free text."
+
+	case model.QuestionTypeNumber:
+		text += "\nPlease reply with a number."
 	}
 
 	// Send the question text first
@@ -515,7 +662,9 @@ func (p *ParticipantBotHandler) askRSVPQuestion(ctx context.Context, userState *
 		}
 	}
 
-	userState.State = model.StateAnsweringRSVPQuestion
+	if guestName == "" {
+		userState.State = model.StateAnsweringRSVPQuestion
+	}
 }
 
 func (p *ParticipantBotHandler) handleRSVPQuestionAnswer(ctx context.Context) {
@@ -526,23 +675,16 @@ func (p *ParticipantBotHandler) handleRSVPQuestionAnswer(ctx context.Context) {
 	question := userState.CurrentEvent.RSVPQuestions[userState.RSVPQuestionIndex]
 
 	// Process the answer based on question type
-	var answers []string
-
-	switch question.Type {
-	case model.QuestionTypeYesNo, model.QuestionTypeMCQ:
-		// Single selection
-		answers = []string{p.update.Message.Text}
-
-	case model.QuestionTypeMultiSelect:
-		// Multiple selection
-		options := strings.Split(p.update.Message.Text, ",")
-		for _, option := range options {
-			answers = append(answers, strings.TrimSpace(option))
+	answers, errText := parseRSVPAnswer(&question, p.update.Message.Text)
+	if errText != "" {
+		_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: p.update.Message.Chat.ID,
+			Text:   errText,
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
 		}
-
-	case model.QuestionTypeShortAnswer:
-		// Short answer
-		answers = []string{p.update.Message.Text}
+		return
 	}
 
 	// Store the answer in the user state
@@ -621,31 +763,64 @@ func (p *ParticipantBotHandler) handleRSVPQuestionAnswer(ctx context.Context) {
 	p.askRSVPQuestion(ctx, userState)
 }
 
+// parseRSVPAnswer turns a reply into the answers stored for the question, returning an error message if it isn't valid
+func parseRSVPAnswer(question *model.RSVPQuestion, text string) ([]string, string) {
+	switch question.Type {
+	case model.QuestionTypeMultiSelect:
+		// Multiple selection
+		var answers []string
+		for _, option := range strings.Split(text, ",") {
+			answers = append(answers, strings.TrimSpace(option))
+		}
+		return answers, ""
+
+	case model.QuestionTypeRating, model.QuestionTypeNumber:
+		// Numeric answers are validated so they can be averaged later
+		answer := strings.TrimSpace(text)
+		value, err := strconv.ParseFloat(answer, 64)
+		if err != nil || (question.Type == model.QuestionTypeRating && !slices.Contains(question.Options, answer)) {
+			if question.Type == model.QuestionTypeRating {
+				return nil, "Please choose a rating from 1 to 5."
+			}
+			return nil, "Please reply with a number."
+		}
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}, ""
+	}
+
+	// Yes/no, MCQ and short answers are stored as given
+	return []string{text}, ""
+}
+
 func (p *ParticipantBotHandler) saveRSVPAnswers(ctx context.Context, userState *model.UserState) {
 	// This function is called when all questions have been answered
-	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      p.update.Message.Chat.ID,
-		Text:        "Thank you for completing the RSVP questions! Your event registration is now complete.",
-		ReplyMarkup: &models.ReplyKeyboardRemove{RemoveKeyboard: true},
-	})
-	if err != nil {
-		log.Println("error sending message:", err)
+	text := "Thank you for completing the RSVP questions! Your event registration is now complete."
+
+	// Applications go to the organisers once the applicant's answers are in
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+	} else if signedUpEvent := findSignedUpEvent(participant, userState.CurrentEvent.ID); signedUpEvent != nil && signedUpEvent.PendingApproval {
+		text = "Thank you for completing the RSVP questions! Your application has been sent to the organisers for approval."
+		p.notifyOrganisersOfApplication(ctx, userState.CurrentEvent, participant)
 	}
 
-	// Send main menu after RSVP completion
 	_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      p.update.Message.Chat.ID,
-		Text:        "What would you like to do next?",
-		ReplyMarkup: getParticipantMainMenuKeyboard(),
+		Text:        text,
+		ReplyMarkup: &models.ReplyKeyboardRemove{RemoveKeyboard: true},
 	})
 	if err != nil {
 		log.Println("error sending message:", err)
 	}
 
 	// Reset the state
+	event := userState.CurrentEvent
 	userState.State = model.StateIdle
 	userState.CurrentEvent = nil
 	userState.RSVPQuestionIndex = 0
+
+	// Guests and the rest of a series can be added before the main menu
+	p.finishRegistration(ctx, userState, event)
 }
 
 // Update the handle check-in method to use the event's check-in code
@@ -669,9 +844,10 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			userState.State = model.StateIdle
 			return
 		}
+		event.ID = eventID
 
 		// Check if event has a check-in code
-		if event.CheckInCode == "" {
+		if !hasCheckInCode(event) {
 			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID: p.update.Message.Chat.ID,
 				Text:   "This event doesn't have a check-in code set by the organizer yet. Please try again later.",
@@ -683,11 +859,12 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			return
 		}
 
-		// Check if it's the event day
-		if event.EventDate.Format(time.DateOnly) != time.Now().Format(time.DateOnly) {
+		// Check that one of the event's check-in windows is open
+		loc := locationForOrganiser(ctx, p.FirebaseConnector, event.UserID)
+		if closedText := checkInClosedText(event, loc, time.Now()); closedText != "" {
 			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID: p.update.Message.Chat.ID,
-				Text:   "You can only check in to this event on the date of the event itself.",
+				Text:   closedText,
 			})
 			if err != nil {
 				log.Println("error sending message:", err)
@@ -717,6 +894,19 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			if signedUpEvent.EventID == eventID {
 				isRegistered = true
 
+				// Applicants can't check in until they are approved
+				if signedUpEvent.PendingApproval {
+					_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+						ChatID: p.update.Message.Chat.ID,
+						Text:   "Your application to join this event is still awaiting the organiser's approval.",
+					})
+					if err != nil {
+						log.Println("error sending message:", err)
+					}
+					userState.State = model.StateIdle
+					return
+				}
+
 				// Check if already checked in
 				if signedUpEvent.CheckedIn {
 					_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
@@ -745,6 +935,27 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			return
 		}
 
+		// Refuse while the user is backed off or locked out after wrong codes
+		if lockedText := p.checkInLockedText(ctx, eventID, userID); lockedText != "" {
+			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+				ChatID: p.update.Message.Chat.ID,
+				Text:   lockedText,
+			})
+			if err != nil {
+				log.Println("error sending message:", err)
+			}
+			userState.State = model.StateIdle
+			return
+		}
+
+		// Events with a check-in radius need the participant's location before the code
+		if requiresCheckInLocation(event) {
+			userState.CurrentEvent = event
+			userState.State = model.StateSharingCheckInLocation
+			p.promptCheckInLocation(ctx)
+			return
+		}
+
 		// Store event in user state and prompt for check-in code
 		userState.CurrentEvent = event
 		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
@@ -764,10 +975,10 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 		event := userState.CurrentEvent
 
 		// Verify the check-in code
-		if enteredCode != event.CheckInCode {
+		if !isValidCheckInCode(event, strings.TrimSpace(enteredCode), time.Now()) {
 			_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID: p.update.Message.Chat.ID,
-				Text:   "Incorrect check-in code. Please try again or contact the event organizer.",
+				Text:   p.recordFailedCheckIn(ctx, event, userID),
 			})
 			if err != nil {
 				log.Println("error sending message:", err)
@@ -793,20 +1004,23 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			return
 		}
 
-		// Find and update the correct signed up event
-		var updated bool
-		for i, signedUpEvent := range participant.SignedUpEvents {
-			if signedUpEvent.EventID == event.ID {
-				participant.SignedUpEvents[i].CheckedIn = true
-				updated = true
-				break
+		// Check in atomically so concurrent scans and sign-ups aren't overwritten
+		_, err = p.FirebaseConnector.CheckInParticipant(ctx, participant.ID, event.ID, time.Now(), userID)
+		if err != nil {
+			text := "Error checking you in. Please try again."
+			switch {
+			case errors.Is(err, model.ErrAlreadyCheckedIn):
+				text = "You have already checked in to this event."
+			case errors.Is(err, model.ErrApplicationPending):
+				text = "Your application to join this event is still awaiting the organiser's approval."
+			case errors.Is(err, model.ErrNotSignedUp):
+				text = "You are not registered for this event. Please join the event first."
+			default:
+				log.Println("error checking in participant:", err)
 			}
-		}
-
-		if !updated {
 			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID: p.update.Message.Chat.ID,
-				Text:   "You are not registered for this event. Please join the event first.",
+				Text:   text,
 			})
 			if err != nil {
 				log.Println("error sending message:", err)
@@ -816,21 +1030,8 @@ func (p *ParticipantBotHandler) handleCheckIn(ctx context.Context) {
 			return
 		}
 
-		// Save the updated participant
-		err = p.FirebaseConnector.UpdateParticipant(ctx, *participant)
-		if err != nil {
-			log.Println("error updating participant:", err)
-			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: p.update.Message.Chat.ID,
-				Text:   "Error checking you in. Please try again.",
-			})
-			if err != nil {
-				log.Println("error sending message:", err)
-			}
-			userState.State = model.StateIdle
-			userState.CurrentEvent = nil
-			return
-		}
+		p.resetCheckInAttempts(ctx, event.ID, userID)
+		notifyCheckIn(event.ID)
 
 		// Success message
 		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
@@ -992,7 +1193,7 @@ func (p *ParticipantBotHandler) handlePersonalNotesReply(ctx context.Context) {
 	userState.State = model.StateIdle
 }
 
-func (p *ParticipantBotHandler) handleJoinEvent(ctx context.Context) {
+func (p *ParticipantBotHandler) handleJoinEvent(ctx context.Context, invite *model.Invite) {
 	userID := p.update.Message.From.ID
 	eventID := p.update.Message.Text
 
@@ -1009,16 +1210,84 @@ func (p *ParticipantBotHandler) handleJoinEvent(ctx context.Context) {
 		}
 		return
 	}
+	event.ID = eventID
+
+	// Only published events can be joined
+	if blockedText := joinBlockedText(event); blockedText != "" {
+		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: userID,
+			Text:   blockedText,
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
+		}
+		userPBotStates[userID].State = model.StateIdle
+		return
+	}
+
+	// Owners can ban users from all of their events; joining is refused if the ban list can't be checked
+	banned, err := p.FirebaseConnector.IsUserBanned(ctx, event.UserID, userID)
+	if err != nil || banned {
+		text := fmt.Sprintf("Sorry, you can't join '%s'. Please contact the organiser if you think this is a mistake.", event.Name)
+		if err != nil {
+			log.Println("error checking ban list:", err)
+			text = fmt.Sprintf("Error joining event '%s'. Please try again.", eventID)
+		}
+		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: userID,
+			Text:   text,
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
+		}
+		userPBotStates[userID].State = model.StateIdle
+		return
+	}
+
+	// Invites are used up on joining; invite-only events can't be joined without one
+	var redeemed *model.Invite
+	if invite != nil || event.InviteOnly {
+		var errText string
+		redeemed, errText = p.redeemInvite(ctx, event, invite, userID)
+		if errText != "" {
+			_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+				ChatID: userID,
+				Text:   errText,
+			})
+			if err != nil {
+				log.Println("error sending message:", err)
+			}
+			userPBotStates[userID].State = model.StateIdle
+			return
+		}
+	}
 
 	// Create participant without a check-in code
 	participant := &model.Participant{
-		UserID: userID,
-		Name:   p.update.Message.From.FirstName,
+		UserID:   userID,
+		Name:     p.update.Message.From.FirstName,
+		Username: p.update.Message.From.Username,
 		// Code field removed
 	}
 
-	err = p.FirebaseConnector.CreateParticipant(ctx, eventID, participant)
-	if err != nil {
+	created, err := p.FirebaseConnector.CreateParticipant(ctx, eventID, participant)
+	if err != nil && redeemed != nil {
+		// The user isn't signed up, so they can try again with the same invite
+		if err := p.FirebaseConnector.ReleaseInvite(ctx, redeemed.Token); err != nil {
+			log.Println("error releasing invite:", err)
+		}
+	}
+	if errors.Is(err, model.ErrEventFull) {
+		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: userID,
+			Text:   fmt.Sprintf("Sorry, '%s' is full.", event.Name),
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
+		}
+		userPBotStates[userID].State = model.StateIdle
+		return
+	} else if err != nil {
 		log.Println("error creating participant:", err)
 		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: userID,
@@ -1059,10 +1328,34 @@ func (p *ParticipantBotHandler) handleJoinEvent(ctx context.Context) {
 		log.Printf("Failed to send event details: %v", err)
 	}
 
+	// Joining an approval-required event only creates an application
+	pending := event.RequiresApproval
+	participant, err = p.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		participant = nil
+	} else if signedUpEvent := findSignedUpEvent(participant, eventID); signedUpEvent != nil {
+		pending = signedUpEvent.PendingApproval
+
+		// Record which invite they joined with
+		if redeemed != nil {
+			signedUpEvent.InviteToken = redeemed.Token
+			signedUpEvent.InviteLabel = redeemed.Label
+			if err := p.FirebaseConnector.UpdateParticipant(ctx, *participant); err != nil {
+				log.Println("error recording invite:", err)
+			}
+		}
+	}
+
 	// Send the confirmation message (without check-in code)
 	joinMessage := fmt.Sprintf(`You have successfully joined event '%s'!
 
-To check in on the day of the event, use the /checkIn command and the organizer will provide you with a 4-digit check-in code.`, event.Name)
+To check in on the day of the event, show your QR ticket at the door, or use the /checkIn command and the organizer will provide you with a 4-digit check-in code.`, event.Name)
+	if pending {
+		joinMessage = fmt.Sprintf("'%s' requires the organiser's approval. I'll let you know once your application has been reviewed.", event.Name)
+	} else if len(event.Agenda) > 0 {
+		joinMessage += fmt.Sprintf("\n\nThis event has %d sessions on its agenda. Use /agenda with the Reference Code %s to pick the ones you want to attend.", len(event.Agenda), eventID)
+	}
 
 	_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: userID,
@@ -1072,6 +1365,19 @@ To check in on the day of the event, use the /checkIn command and the organizer
 		log.Println("error sending message:", err)
 	}
 
+	// Send the participant their QR ticket; applicants get theirs once approved
+	if participant != nil {
+		if !pending {
+			if err := p.sendTicket(ctx, userID, event, participant); err != nil {
+				log.Println("error sending ticket:", err)
+			}
+		}
+		// Re-opening the join link shouldn't alert the organisers again
+		if created {
+			p.alertIfChronicNoShow(ctx, event, participant)
+		}
+	}
+
 	// If the event has RSVP questions, start the RSVP flow immediately
 	if event != nil && len(event.RSVPQuestions) > 0 {
 		time.Sleep(1 * time.Second) // Small delay for better UX
@@ -1094,18 +1400,14 @@ To check in on the day of the event, use the /checkIn command and the organizer
 		p.askRSVPQuestion(ctx, userState)
 	} else {
 		// No RSVP questions, joining is complete
+		if pending && participant != nil {
+			p.notifyOrganisersOfApplication(ctx, event, participant)
+		}
 		userState := userPBotStates[userID]
 		userState.State = model.StateIdle
 
-		// Send main menu after joining
-		_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID:      userID,
-			Text:        "What would you like to do next?",
-			ReplyMarkup: getParticipantMainMenuKeyboard(),
-		})
-		if err != nil {
-			log.Println("error sending message:", err)
-		}
+		// Guests and the rest of a series can be added before the main menu
+		p.finishRegistration(ctx, userState, event)
 	}
 }
 
@@ -1142,14 +1444,15 @@ func (p *ParticipantBotHandler) viewEventsHandler(ctx context.Context, past bool
 			log.Println(fmt.Sprintf("error reading event with event(ID: %s): %v", event.ID, err))
 			continue
 		}
-		if past {
-			if event.EventDate.After(time.Now()) {
-				continue
-			}
-		} else {
-			if event.EventDate.Before(time.Now()) {
-				continue
-			}
+		// Deleted events stay hidden while they can still be restored
+		if event.IsDeleted() {
+			continue
+		}
+
+		// Completed events are past even if the organiser closed them early
+		isPast := event.EventDate.Before(time.Now()) || event.CurrentStatus() == model.EventStatusCompleted
+		if past != isPast {
+			continue
 		}
 
 		// Check if this event has unanswered RSVP questions
@@ -1229,6 +1532,21 @@ func (p *ParticipantBotHandler) viewEventsHandler(ctx context.Context, past bool
 			messageText += fmt.Sprintf("- %s (Event ID: %s)\n",
 				event.Name, event.ID)
 			messageText += fmt.Sprintf("  Date: %s\n", event.EventDate.Format("2006-01-02"))
+			if event.Venue != nil {
+				messageText += fmt.Sprintf("  Venue: %s\n", strings.ReplaceAll(formatVenue(event.Venue), "\n", ", "))
+			}
+			if event.CurrentStatus() == model.EventStatusCancelled {
+				messageText += "  ❌ Cancelled"
+				if event.CancelReason != "" {
+					messageText += ": " + event.CancelReason
+				}
+				messageText += "\n"
+			}
+			if participant != nil {
+				if signedUpEvent := findSignedUpEvent(participant, event.ID); signedUpEvent != nil && signedUpEvent.PendingApproval {
+					messageText += "  ⏳ Awaiting the organiser's approval\n"
+				}
+			}
 			if len(event.EventDetails) > 0 {
 				messageText += "  Details:\n"
 				for _, detail := range event.EventDetails {
@@ -1294,15 +1612,25 @@ func (p *ParticipantBotHandler) viewEventsHandler(ctx context.Context, past bool
 }
 
 func (p *ParticipantBotHandler) sendEventDetailsWithImages(ctx context.Context, event *model.Event) error {
-	// First send the event name and date
+	// First send the event name, date and venue
+	text := fmt.Sprintf("Event: %s\nDate: %s", event.Name, event.EventDate.Format("2006-01-02"))
+	if event.Venue != nil {
+		text += "\nVenue: " + formatVenue(event.Venue)
+	}
 	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: p.update.Message.Chat.ID,
-		Text:   fmt.Sprintf("Event: %s\nDate: %s", event.Name, event.EventDate.Format("2006-01-02")),
+		Text:   text,
 	})
 	if err != nil {
 		return err
 	}
 
+	// Send the venue as a map pin participants can open in their maps app
+	err = sendVenue(ctx, p.bot, p.update.Message.Chat.ID, event.Venue)
+	if err != nil {
+		log.Printf("Failed to send venue: %v", err)
+	}
+
 	// If the event has an EDM image, send it
 	if event.EDMFileURL != "" && event.EDMFileURL != "N/A" {
 		err := downloadSendAndDeleteImage(
@@ -1379,7 +1707,14 @@ func getParticipantMainMenuKeyboard() *models.ReplyKeyboardMarkup {
 				{Text: "/notes"},
 			},
 			{
+				{Text: "/myTicket"},
 				{Text: "/pastEvents"},
+			},
+			{
+				{Text: "/agenda"},
+				{Text: "/guests"},
+			},
+			{
 				{Text: "/help"},
 			},
 		},
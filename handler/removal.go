@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func (o *OrganiserBotHandler) handleRemoveParticipant(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.SplitN(strings.TrimSpace(update.Message.Text), " ", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+		return "Invalid format. Please use: EVENT_REF_CODE NAME_OR_USER_ID\nExample: ABC123 Alice"
+	}
+	eventID, query := parts[0], strings.TrimSpace(parts[1])
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionManageParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to remove participants from this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error retrieving event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	participants, err := o.FirebaseConnector.ListParticipants(ctx, eventID)
+	if err != nil {
+		log.Printf("error reading participants for event(ID: %s): %v\n", eventID, err)
+		return "Error retrieving participants. Please try again."
+	}
+
+	matches := matchParticipants(participants, query)
+	switch {
+	case len(matches) == 0:
+		return fmt.Sprintf("No participant of '%s' matches '%s'.", event.Name, query)
+	case len(matches) == 1:
+		userState.CurrentEvent = event
+		userState.TempOptions = []string{matches[0].ID}
+		userState.State = model.StateConfirmRemoveParticipant
+		return removeParticipantPrompt(&matches[0], event)
+	case len(matches) > maxManualCheckInMatches:
+		return fmt.Sprintf("%d participants match '%s'. Please be more specific or use their User ID.", len(matches), query)
+	}
+
+	loc := o.organiserLocation(ctx, update.Message.From.ID)
+	text := fmt.Sprintf("Several participants match '%s'. Reply with the number of the one to remove, or 'Cancel':\n", query)
+	userState.TempOptions = nil
+	for i := range matches {
+		text += fmt.Sprintf("%d. %s\n", i+1, describeCheckInStatus(&matches[i], event.ID, loc))
+		userState.TempOptions = append(userState.TempOptions, matches[i].ID)
+	}
+
+	userState.CurrentEvent = event
+	userState.State = model.StateSelectRemoveParticipantMatch
+	return text
+}
+
+func (o *OrganiserBotHandler) handleSelectRemoveParticipantMatch(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	if update.Message.Text == "Cancel" {
+		resetManualCheckInState(userState)
+		return "Operation cancelled."
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(update.Message.Text))
+	if err != nil || choice < 1 || choice > len(userState.TempOptions) {
+		return fmt.Sprintf("Please reply with a number between 1 and %d, or 'Cancel'.", len(userState.TempOptions))
+	}
+
+	participant, err := o.FirebaseConnector.ReadParticipantByID(ctx, userState.TempOptions[choice-1])
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		resetManualCheckInState(userState)
+		return "Error retrieving the participant. Please try again."
+	}
+
+	userState.TempOptions = []string{participant.ID}
+	userState.State = model.StateConfirmRemoveParticipant
+	return removeParticipantPrompt(participant, userState.CurrentEvent)
+}
+
+func removeParticipantPrompt(participant *model.Participant, event *model.Event) string {
+	return fmt.Sprintf("Remove %s [ID: %d] from '%s'?\n"+
+		"1. Remove and notify them\n"+
+		"2. Remove without notifying them\n"+
+		"3. Remove and ban them from the owner's future events\n"+
+		"4. Cancel", participant.Name, participant.UserID, event.Name)
+}
+
+func (o *OrganiserBotHandler) handleConfirmRemoveParticipant(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	var notify, ban bool
+	switch strings.TrimSpace(update.Message.Text) {
+	case "1":
+		notify = true
+	case "2":
+	case "3":
+		ban = true
+	case "4", "Cancel":
+		resetManualCheckInState(userState)
+		return "Operation cancelled."
+	default:
+		return "Invalid option. Please choose 1-4."
+	}
+
+	event := userState.CurrentEvent
+	participantID := userState.TempOptions[0]
+	resetManualCheckInState(userState)
+
+	participant, err := o.FirebaseConnector.RemoveParticipantFromEvent(ctx, event.ID, participantID)
+	if errors.Is(err, model.ErrNotSignedUp) {
+		return "This participant is no longer registered for the event."
+	} else if err != nil {
+		log.Println("error removing participant:", err)
+		return "Error removing the participant. Please try again."
+	}
+
+	organiserID := update.Message.From.ID
+	err = o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      event.ID,
+		Action:       model.AuditActionRemoved,
+		ActorID:      organiserID,
+		TargetUserID: participant.UserID,
+		At:           time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+	notifyCheckIn(event.ID)
+
+	text := fmt.Sprintf("%s has been removed from '%s'.", participant.Name, event.Name)
+
+	if ban {
+		// Joins are checked against the owner's ban list, so coowners ban on the owner's behalf
+		err = o.FirebaseConnector.BanUser(ctx, event.UserID, model.BannedUser{
+			UserID:   participant.UserID,
+			Name:     participant.Name,
+			Reason:   fmt.Sprintf("Removed from '%s'", event.Name),
+			BannedAt: time.Now().UTC(),
+		})
+		isOwner := organiserID == event.UserID
+		if err != nil && !errors.Is(err, model.ErrAlreadyBanned) {
+			log.Println("error banning user:", err)
+			if isOwner {
+				return text + "\nError adding them to your ban list. Please try /ban " + strconv.FormatInt(participant.UserID, 10)
+			}
+			return text + "\nError adding them to the owner's ban list. Please ask the owner to /ban " + strconv.FormatInt(participant.UserID, 10)
+		}
+		if isOwner {
+			text += " They can no longer join your events."
+		} else {
+			text += " They can no longer join the owner's events."
+		}
+	}
+
+	if notify {
+		participantBot, err := newParticipantBot()
+		if err == nil {
+			_, err = participantBot.SendMessage(ctx, &bot.SendMessageParams{
+				ChatID: participant.UserID,
+				Text:   fmt.Sprintf("You have been removed from the event '%s' by the organiser.", event.Name),
+			})
+		}
+		if err != nil {
+			log.Printf("error notifying removed participant %d: %v", participant.UserID, err)
+			return text + "\nThey could not be notified."
+		}
+		text += " They have been notified."
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleBanUser(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.SplitN(strings.TrimSpace(update.Message.Text), " ", 2)
+	userID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "Invalid User ID. Please use: USER_ID [REASON]\nYou can find User IDs with /listParticipants."
+	}
+	banned := model.BannedUser{UserID: userID, BannedAt: time.Now().UTC()}
+	if len(parts) == 2 {
+		banned.Reason = strings.TrimSpace(parts[1])
+	}
+
+	participant, err := o.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+	if err != nil {
+		log.Println("error reading participant:", err)
+	} else if participant != nil {
+		banned.Name = participant.Name
+	}
+
+	err = o.FirebaseConnector.BanUser(ctx, update.Message.From.ID, banned)
+	if errors.Is(err, model.ErrAlreadyBanned) {
+		return fmt.Sprintf("User %d is already banned from your events.", userID)
+	} else if err != nil {
+		log.Println("error banning user:", err)
+		return "Error updating your ban list. Please try again."
+	}
+	return fmt.Sprintf("%s can no longer join your events. Use /removeParticipant to take them off events they already joined.", describeBannedUser(banned))
+}
+
+func (o *OrganiserBotHandler) handleUnbanUser(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	userID, err := strconv.ParseInt(strings.TrimSpace(update.Message.Text), 10, 64)
+	if err != nil {
+		return "Invalid User ID. Please provide a valid Telegram User ID (numeric)."
+	}
+
+	err = o.FirebaseConnector.UnbanUser(ctx, update.Message.From.ID, userID)
+	if errors.Is(err, model.ErrNotBanned) {
+		return fmt.Sprintf("User %d is not on your ban list.", userID)
+	} else if err != nil {
+		log.Println("error unbanning user:", err)
+		return "Error updating your ban list. Please try again."
+	}
+	return fmt.Sprintf("User %d can join your events again.", userID)
+}
+
+func (o *OrganiserBotHandler) handleListBannedUsers(ctx context.Context, userID int64) string {
+	organiser, err := o.FirebaseConnector.ReadOrganiser(ctx, userID)
+	if err != nil {
+		log.Println("error reading organiser:", err)
+		return "Error retrieving your ban list. Please try again."
+	}
+	if organiser == nil || len(organiser.BannedUsers) == 0 {
+		return "You have not banned anyone."
+	}
+
+	loc := o.organiserLocation(ctx, userID)
+	text := "Banned users:\n"
+	for _, banned := range organiser.BannedUsers {
+		text += fmt.Sprintf("- %s, since %s", describeBannedUser(banned), banned.BannedAt.In(loc).Format("2006-01-02"))
+		if banned.Reason != "" {
+			text += fmt.Sprintf(" (%s)", banned.Reason)
+		}
+		text += "\n"
+	}
+	return text + "\nUse /unban <User_ID> to lift a ban."
+}
+
+func describeBannedUser(banned model.BannedUser) string {
+	if banned.Name == "" {
+		return fmt.Sprintf("User %d", banned.UserID)
+	}
+	return fmt.Sprintf("%s [ID: %d]", banned.Name, banned.UserID)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/go-telegram/bot/models"
+)
+
+var coownerRoleDescriptions = map[model.CoownerRole]string{
+	model.RoleOwner:     "everything, including deleting the event",
+	model.RoleAdmin:     "everything except deleting the event",
+	model.RoleEditor:    "edit the event, manage sign-ups and view participants",
+	model.RoleMessenger: "send blasts and view participants",
+	model.RoleDoorStaff: "check-in only",
+	model.RoleViewer:    "view participants and reports",
+}
+
+// describeCoownerRoles lists the roles a coowner can be given and what each allows
+func describeCoownerRoles() string {
+	text := ""
+	for _, role := range model.CoownerRoles {
+		text += fmt.Sprintf("- %s: %s\n", role, coownerRoleDescriptions[role])
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleSettingCoownerRole(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 3 {
+		return "Invalid format. Please use: EVENT_REF_CODE USER_ID ROLE\nExample: ABC123 123456789 door"
+	}
+	eventID, coownerID, errText := parseEventAndUserID(parts[0] + " " + parts[1])
+	if errText != "" {
+		return errText
+	}
+	role, ok := model.ParseCoownerRole(strings.ToLower(parts[2]))
+	if !ok {
+		return "Unknown role. Please choose one of:\n" + describeCoownerRoles()
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionManageTeam)
+	if err != nil {
+		log.Println("error checking event permissions:", err)
+		return fmt.Sprintf("Error checking permissions for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to change roles for this event."
+	}
+
+	err = o.FirebaseConnector.SetCoownerRole(ctx, eventID, coownerID, role)
+	if err != nil {
+		log.Println("error setting coowner role:", err)
+		return fmt.Sprintf("Error changing the role: %v", err)
+	}
+	return fmt.Sprintf("User %d is now %s on event %s (%s).", coownerID, role, eventID, coownerRoleDescriptions[role])
+}
+
+// describeTeam lists the event's owner and coowners with their roles
+func describeTeam(event *model.Event) string {
+	text := fmt.Sprintf("    - %d: %s\n", event.UserID, model.RoleOwner)
+	for _, coownerID := range event.Coowners {
+		text += fmt.Sprintf("    - %d: %s\n", coownerID, event.RoleOf(coownerID))
+	}
+	return text
+}
@@ -0,0 +1,539 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	minSeriesWeeks = 2
+	maxSeriesWeeks = 52
+
+	// Marks an /editEvent that should also change the later sessions of a series
+	seriesEditAll = "all"
+
+	// How many participants /seriesAttendance lists by name
+	maxSeriesAttendanceRows = 30
+)
+
+var seriesRulePattern = regexp.MustCompile(`(?i)^every\s+([a-z]+)\s+(?:at\s+)?(\d{1,2}:\d{2})\s+for\s+(\d+)\s+weeks?$`)
+
+// parseSeriesRule reads a rule like "every Tuesday at 19:00 for 10 weeks", returning an error message if it can't
+func parseSeriesRule(text string) (time.Weekday, string, int, string) {
+	invalid := "Please describe the series like this: every Tuesday at 19:00 for 10 weeks"
+
+	match := seriesRulePattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return 0, "", 0, invalid
+	}
+
+	weekday := -1
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if strings.EqualFold(match[1], name) || strings.EqualFold(match[1], name[:3]) {
+			weekday = int(day)
+			break
+		}
+	}
+	if weekday < 0 {
+		return 0, "", 0, fmt.Sprintf("'%s' is not a day of the week. %s", match[1], invalid)
+	}
+
+	startTime, err := time.Parse("15:04", match[2])
+	if err != nil {
+		return 0, "", 0, fmt.Sprintf("'%s' is not a valid time. Use the 24-hour format, e.g. 19:00.", match[2])
+	}
+
+	weeks, _ := strconv.Atoi(match[3])
+	if weeks < minSeriesWeeks || weeks > maxSeriesWeeks {
+		return 0, "", 0, fmt.Sprintf("A series can run for %d to %d weeks.", minSeriesWeeks, maxSeriesWeeks)
+	}
+	return time.Weekday(weekday), startTime.Format("15:04"), weeks, ""
+}
+
+// describeOccurrence renders a session of a series on one line
+func describeOccurrence(event *model.Event) string {
+	text := fmt.Sprintf("Session %d: %s", event.SeriesIndex, event.EventDate.Format("Mon 2006-01-02"))
+	if event.StartTime != "" {
+		text += " " + event.StartTime
+	}
+	return text
+}
+
+func (o *OrganiserBotHandler) handleCreatingSeries(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, rule, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	weekday, startTime, weeks, errText := parseSeriesRule(rule)
+	if errText != "" {
+		return errText
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to turn this event into a series."
+	}
+
+	base, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	base.ID = eventID
+	if base.SeriesID != "" {
+		return fmt.Sprintf("'%s' is already part of a series. See it with /seriesAttendance %s.", base.Name, eventID)
+	}
+	if base.CurrentStatus() == model.EventStatusCancelled {
+		return fmt.Sprintf("'%s' has been cancelled and can't start a series.", base.Name)
+	}
+
+	// The event becomes the first session, moved forward to the series' weekday if needed
+	firstDate := base.EventDate
+	for firstDate.Weekday() != weekday {
+		firstDate = firstDate.AddDate(0, 0, 1)
+	}
+	if firstDate.Before(time.Now()) {
+		return fmt.Sprintf("The first session would be on %s, which has already passed. Move the event to a later date with /editEvent first.",
+			firstDate.Format("2006-01-02"))
+	}
+
+	seriesID, err := o.FirebaseConnector.CreateSeries(ctx, model.EventSeries{
+		Name:      base.Name,
+		OwnerID:   base.UserID,
+		Rule:      strings.TrimSpace(rule),
+		Weekday:   weekday,
+		StartTime: startTime,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error creating series:", err)
+		return "Error creating the series. Please try again."
+	}
+
+	base.EventDate = firstDate
+	base.SeriesID = seriesID
+	base.SeriesIndex = 1
+	base.StartTime = startTime
+	err = o.FirebaseConnector.UpdateEvent(ctx, eventID, *base)
+	if err != nil {
+		log.Println("error updating event:", err)
+		return "Error creating the series. Please try again."
+	}
+
+	sessions := []*model.Event{base}
+	for index := 2; index <= weeks; index++ {
+		occurrence := base.Blueprint()
+		occurrence.Name = base.Name
+		occurrence.EventDate = firstDate.AddDate(0, 0, 7*(index-1))
+		occurrence.Status = base.CurrentStatus()
+		occurrence.StatusChangedAt = time.Now().UTC()
+		occurrence.CheckInCode = base.CheckInCode
+		occurrence.CheckInCodeSecret = base.CheckInCodeSecret
+		occurrence.CheckInCodePeriod = base.CheckInCodePeriod
+		occurrence.SeriesID = seriesID
+		occurrence.SeriesIndex = index
+		occurrence.StartTime = startTime
+
+		refKey, err := o.FirebaseConnector.CreateEvent(ctx, occurrence)
+		if err == nil {
+			occurrence.ID = refKey
+			err = o.FirebaseConnector.UpdateEvent(ctx, refKey, occurrence)
+		}
+		if err != nil {
+			log.Printf("error creating session %d of series %s: %v", index, seriesID, err)
+			break
+		}
+		sessions = append(sessions, &occurrence)
+	}
+
+	series, err := o.FirebaseConnector.ReadSeries(ctx, seriesID)
+	if err != nil {
+		log.Println("error reading series:", err)
+	} else {
+		for _, session := range sessions {
+			series.EventIDs = append(series.EventIDs, session.ID)
+		}
+		if err := o.FirebaseConnector.UpdateSeries(ctx, *series); err != nil {
+			log.Println("error updating series:", err)
+		}
+	}
+
+	text := fmt.Sprintf("'%s' now runs %s.\n\n", base.Name, strings.TrimSpace(rule))
+	if len(sessions) < weeks {
+		text = fmt.Sprintf("Only %d of the %d sessions could be created. Please try /createSeries again later for the rest.\n\n", len(sessions), weeks)
+	}
+	for _, session := range sessions {
+		text += fmt.Sprintf("%s (Reference Code: %s)\n", describeOccurrence(session), session.ID)
+	}
+	if base.CurrentStatus() == model.EventStatusDraft {
+		text += fmt.Sprintf("\nThe sessions are drafts. /publishEvent %s publishes them all.", eventID)
+	} else {
+		text += "\nParticipants who join a session will be offered the rest of the series."
+	}
+	return text
+}
+
+// publishSeriesDrafts publishes the other draft sessions of the event's series, returning how many were published
+func (o *OrganiserBotHandler) publishSeriesDrafts(ctx context.Context, event *model.Event) int {
+	sessions, err := o.FirebaseConnector.ListSeriesEvents(ctx, event.SeriesID)
+	if err != nil {
+		log.Println("error listing series sessions:", err)
+		return 0
+	}
+
+	published := 0
+	for _, session := range sessions {
+		if session.ID == event.ID || session.IsDeleted() || session.CurrentStatus() != model.EventStatusDraft {
+			continue
+		}
+		_, err := o.FirebaseConnector.SetEventStatus(ctx, session.ID, model.EventStatusPublished, "", time.Now())
+		if err != nil {
+			log.Printf("error publishing session %s: %v", session.ID, err)
+			continue
+		}
+		published++
+	}
+	return published
+}
+
+// editEventOptionsText lists what /editEvent can change
+func editEventOptionsText(event *model.Event) string {
+	return fmt.Sprintf("Editing event '%s'. Choose what you want to edit:\n"+
+		"1. Event Name\n"+
+		"2. Event Date\n"+
+		"3. Event Details\n"+
+		"4. Venue\n"+
+		"5. Cancel Edit", event.Name)
+}
+
+func (o *OrganiserBotHandler) handleSelectSeriesEditScope(update *models.Update, userState *model.UserState) string {
+	switch strings.TrimSpace(update.Message.Text) {
+	case "1":
+		userState.TempOptions = nil
+	case "2":
+		userState.TempOptions = []string{seriesEditAll}
+	case "3", "Cancel":
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return "Event editing cancelled."
+	default:
+		return "Invalid option. Please choose 1-3."
+	}
+
+	userState.State = model.StateSelectEditOption
+	return editEventOptionsText(userState.CurrentEvent)
+}
+
+// editsWholeSeries reports whether the current /editEvent also applies to the later sessions of the series
+func editsWholeSeries(userState *model.UserState) bool {
+	return userState.CurrentEvent != nil && userState.CurrentEvent.SeriesID != "" && slices.Contains(userState.TempOptions, seriesEditAll)
+}
+
+// applyToLaterOccurrences copies an /editEvent change to the later sessions when the organiser chose to edit the whole series,
+// returning a note for the organiser and the sessions it updated
+func (o *OrganiserBotHandler) applyToLaterOccurrences(ctx context.Context, userState *model.UserState, change func(event *model.Event)) (string, []model.Event) {
+	if !editsWholeSeries(userState) {
+		return "", nil
+	}
+	event := userState.CurrentEvent
+	userState.TempOptions = nil
+
+	updated, err := o.FirebaseConnector.UpdateLaterOccurrences(ctx, event.SeriesID, event.SeriesIndex, change)
+	if err != nil {
+		log.Println("error updating later sessions:", err)
+		return fmt.Sprintf("\nOnly %d later sessions could be updated. Please try again for the rest.", len(updated)), updated
+	}
+	return fmt.Sprintf("\nAlso updated %d later sessions.", len(updated)), updated
+}
+
+func (o *OrganiserBotHandler) handleSeriesAttendance(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionViewParticipants)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to view the attendance of this series."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if event.SeriesID == "" {
+		return fmt.Sprintf("'%s' is not part of a series. Start one with /createSeries %s every <Day> at <HH:MM> for <N> weeks.", event.Name, eventID)
+	}
+
+	sessions, err := o.FirebaseConnector.ListSeriesEvents(ctx, event.SeriesID)
+	if err != nil {
+		log.Println("error listing series sessions:", err)
+		return "Error retrieving the sessions of this series. Please try again."
+	}
+
+	type attendee struct {
+		name       string
+		registered int
+		attended   int
+	}
+	attendees := make(map[int64]*attendee)
+	var totalRegistered, totalAttended int
+
+	text := fmt.Sprintf("Attendance for the series '%s':\n\n", event.Name)
+	for i := range sessions {
+		session := &sessions[i]
+		if session.IsDeleted() {
+			continue
+		}
+
+		participants, err := o.FirebaseConnector.ListParticipants(ctx, session.ID)
+		if err != nil {
+			log.Printf("error listing participants of session %s: %v", session.ID, err)
+			text += fmt.Sprintf("%s: could not be read\n", describeOccurrence(session))
+			continue
+		}
+
+		registered, attended := 0, 0
+		for j := range participants {
+			signedUpEvent := findSignedUpEvent(&participants[j], session.ID)
+			if signedUpEvent == nil || signedUpEvent.PendingApproval {
+				continue
+			}
+			a, ok := attendees[participants[j].UserID]
+			if !ok {
+				a = &attendee{name: participants[j].Name}
+				attendees[participants[j].UserID] = a
+			}
+			registered++
+			a.registered++
+			if signedUpEvent.CheckedIn {
+				attended++
+				a.attended++
+			}
+		}
+		totalRegistered += registered
+		totalAttended += attended
+
+		text += fmt.Sprintf("%s - %d registered, %d checked in", describeOccurrence(session), registered, attended)
+		if status := session.CurrentStatus(); status != model.EventStatusPublished {
+			text += fmt.Sprintf(" (%s)", strings.ToLower(eventStatusLabels[status]))
+		}
+		text += "\n"
+	}
+
+	text += fmt.Sprintf("\nTotal: %d sign-ups, %d check-ins from %d people\n", totalRegistered, totalAttended, len(attendees))
+	if len(attendees) == 0 {
+		return text
+	}
+
+	userIDs := make([]int64, 0, len(attendees))
+	for userID := range attendees {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		a, b := attendees[userIDs[i]], attendees[userIDs[j]]
+		if a.attended != b.attended {
+			return a.attended > b.attended
+		}
+		return a.name < b.name
+	})
+
+	text += "\nSessions attended per participant:\n"
+	for i, userID := range userIDs {
+		if i == maxSeriesAttendanceRows {
+			text += fmt.Sprintf("...and %d more\n", len(userIDs)-maxSeriesAttendanceRows)
+			break
+		}
+		a := attendees[userID]
+		text += fmt.Sprintf("- %s (%d): %d of %d\n", a.name, userID, a.attended, a.registered)
+	}
+	return text
+}
+
+// laterOccurrencesToJoin returns the upcoming sessions after event that the participant could also join
+func (p *ParticipantBotHandler) laterOccurrencesToJoin(ctx context.Context, event *model.Event, participant *model.Participant) []model.Event {
+	joined := findSignedUpEvent(participant, event.ID)
+	if joined == nil || joined.PendingApproval {
+		return nil
+	}
+
+	sessions, err := p.FirebaseConnector.ListSeriesEvents(ctx, event.SeriesID)
+	if err != nil {
+		log.Println("error listing series sessions:", err)
+		return nil
+	}
+
+	var later []model.Event
+	for _, session := range sessions {
+		switch {
+		case session.SeriesIndex <= event.SeriesIndex,
+			joinBlockedText(&session) != "",
+			session.EventDate.Before(time.Now().Truncate(24 * time.Hour)),
+			findSignedUpEvent(participant, session.ID) != nil:
+			continue
+		case session.RequiresApproval:
+			// Each application is reviewed on its own
+			continue
+		case session.InviteOnly:
+			// Invites belong to a single session, so each one needs its own
+			continue
+		case session.IsFull():
+			continue
+		}
+		later = append(later, session)
+	}
+	return later
+}
+
+// offerSeriesJoin asks a participant who joined one session whether to join the rest of the series, returning false if there is nothing to offer
+func (p *ParticipantBotHandler) offerSeriesJoin(ctx context.Context, userState *model.UserState, event *model.Event) bool {
+	if event == nil || event.SeriesID == "" {
+		return false
+	}
+
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, p.update.Message.From.ID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		return false
+	}
+	later := p.laterOccurrencesToJoin(ctx, event, participant)
+	if len(later) == 0 {
+		return false
+	}
+
+	text := fmt.Sprintf("'%s' is a weekly series. Do you also want to join the %d later sessions?\n", event.Name, len(later))
+	for i := range later {
+		text += describeOccurrence(&later[i]) + "\n"
+	}
+	if len(event.RSVPQuestions) > 0 {
+		text += "\nYour RSVP answers will be used for every session."
+	}
+
+	_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: p.update.Message.Chat.ID,
+		Text:   text,
+		ReplyMarkup: &models.ReplyKeyboardMarkup{
+			Keyboard: [][]models.KeyboardButton{
+				{
+					{Text: "Yes"},
+					{Text: "No"},
+				},
+			},
+			ResizeKeyboard:  true,
+			OneTimeKeyboard: true,
+		},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+		return false
+	}
+
+	userState.CurrentEvent = event
+	userState.State = model.StateJoiningSeries
+	return true
+}
+
+func (p *ParticipantBotHandler) handleJoiningSeries(ctx context.Context, userState *model.UserState) {
+	event := userState.CurrentEvent
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+	userID := p.update.Message.From.ID
+
+	text := fmt.Sprintf("Okay, you're only joining this session of '%s'. You can join other sessions with their links.", event.Name)
+	if strings.EqualFold(strings.TrimSpace(p.update.Message.Text), "yes") {
+		text = p.joinLaterOccurrences(ctx, event, userID)
+	}
+
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: getParticipantMainMenuKeyboard(),
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// joinLaterOccurrences signs the participant up for the rest of the series with the RSVP answers they gave for event
+func (p *ParticipantBotHandler) joinLaterOccurrences(ctx context.Context, event *model.Event, userID int64) string {
+	participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+	if err != nil || participant == nil {
+		log.Println("error reading participant:", err)
+		return "Error joining the other sessions. Please try again with their links."
+	}
+	joined := findSignedUpEvent(participant, event.ID)
+	later := p.laterOccurrencesToJoin(ctx, event, participant)
+
+	var joinedSessions, fullSessions []model.Event
+	for _, session := range later {
+		_, err := p.FirebaseConnector.CreateParticipant(ctx, session.ID, &model.Participant{
+			UserID:   userID,
+			Name:     p.update.Message.From.FirstName,
+			Username: p.update.Message.From.Username,
+		})
+		if errors.Is(err, model.ErrEventFull) {
+			fullSessions = append(fullSessions, session)
+			continue
+		} else if err != nil {
+			log.Printf("error joining session %s: %v", session.ID, err)
+			continue
+		}
+		joinedSessions = append(joinedSessions, session)
+	}
+
+	fullText := ""
+	if len(fullSessions) > 0 {
+		fullText = "\nThese sessions filled up before you could join:\n"
+		for i := range fullSessions {
+			fullText += describeOccurrence(&fullSessions[i]) + "\n"
+		}
+	}
+	if len(joinedSessions) == 0 {
+		if fullText != "" {
+			return "Sorry, you couldn't join any more sessions." + fullText
+		}
+		return "Error joining the other sessions. Please try again with their links."
+	}
+
+	// Sessions share their RSVP questions, so the answers carry over
+	if joined != nil && len(joined.RSVPAnswers) > 0 {
+		participant, err = p.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+		if err != nil || participant == nil {
+			log.Println("error reading participant:", err)
+		} else {
+			for _, session := range joinedSessions {
+				if signedUpEvent := findSignedUpEvent(participant, session.ID); signedUpEvent != nil {
+					signedUpEvent.RSVPAnswers = slices.Clone(joined.RSVPAnswers)
+				}
+			}
+			if err := p.FirebaseConnector.UpdateParticipant(ctx, *participant); err != nil {
+				log.Println("error copying RSVP answers:", err)
+			}
+		}
+	}
+
+	text := fmt.Sprintf("You have joined %d more sessions of '%s':\n", len(joinedSessions), event.Name)
+	for i := range joinedSessions {
+		text += describeOccurrence(&joinedSessions[i]) + "\n"
+	}
+	return text + fullText + "\nUse /myTicket on the day to get the ticket for each session."
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const blankEventOption = "Blank event"
+
+func (o *OrganiserBotHandler) handleCloningEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	eventID := strings.TrimSpace(update.Message.Text)
+	organiserID := update.Message.From.ID
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, organiserID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to clone this event."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	blueprint := event.Blueprint()
+	blueprint.HandOver(organiserID)
+	userState.CurrentEvent = &blueprint
+	userState.State = model.StateEnteringCopyName
+	return fmt.Sprintf("Cloning '%s' with %d details and %d RSVP questions. The copy starts as a draft.\nWhat's the name of the new event? Send 'Cancel' to stop.",
+		event.Name, len(blueprint.EventDetails), len(blueprint.RSVPQuestions))
+}
+
+func (o *OrganiserBotHandler) handleEnteringCopyName(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	if update.Message.Text == "Cancel" {
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	}
+
+	userState.CurrentEvent.Name = strings.TrimSpace(update.Message.Text)
+	userState.State = model.StateEnteringCopyDate
+	sendKeyboardPrompt(ctx, b, chatID, "Great! Now, please send me the date of the event in this format: 'YYYY-MM-DD'.", "Cancel")
+}
+
+func (o *OrganiserBotHandler) handleEnteringCopyDate(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	if update.Message.Text == "Cancel" {
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	}
+
+	eventDate, err := time.Parse("2006-01-02", update.Message.Text)
+	if err != nil || eventDate.Before(time.Now()) {
+		sendKeyboardPrompt(ctx, b, chatID, "Invalid date format. Please use 'YYYY-MM-DD' (e.g., 2023-12-25) and ensure it's not in the past.", "Cancel")
+		return
+	}
+
+	event := userState.CurrentEvent
+	event.EventDate = eventDate
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+
+	refKey, err := o.saveEvent(ctx, event)
+	if err != nil {
+		log.Println("error creating event:", err)
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatID,
+			Text:        "Error creating event. Please try again.",
+			ReplyMarkup: getOrganizerMainMenuKeyboard(),
+		})
+		if err != nil {
+			log.Println("error sending message:", err)
+		}
+		return
+	}
+
+	err = o.sendEventCreationConfirmation(ctx, b, chatID, event, refKey, len(event.RSVPQuestions) > 0)
+	if err != nil {
+		log.Println("error sending confirmation:", err)
+	}
+}
+
+func (o *OrganiserBotHandler) handleSavingTemplate(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, name, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "Invalid format. Please use: EVENT_REF_CODE TEMPLATE_NAME\nExample: ABC123 Monthly meetup"
+	}
+	organiserID := update.Message.From.ID
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, organiserID, model.PermissionEditEvent)
+	if err != nil {
+		log.Println("error checking event ownership:", err)
+		return fmt.Sprintf("Error checking ownership for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !allowed {
+		return "You don't have permission to save this event as a template."
+	}
+
+	templates, err := o.FirebaseConnector.ListTemplatesByOwnerID(ctx, organiserID)
+	if err != nil {
+		log.Println("error listing templates:", err)
+		return "Error retrieving your templates. Please try again."
+	}
+	if findTemplate(templates, name) != nil {
+		return fmt.Sprintf("You already have a template called '%s'. Remove it with /deleteTemplate %s or choose another name.", name, name)
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+
+	blueprint := event.Blueprint()
+	blueprint.HandOver(organiserID)
+	_, err = o.FirebaseConnector.CreateTemplate(ctx, model.EventTemplate{
+		Name:      name,
+		OwnerID:   organiserID,
+		Event:     blueprint,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error creating template:", err)
+		return "Error saving the template. Please try again."
+	}
+	return fmt.Sprintf("Saved '%s' as the template '%s'. Pick it when you next use /addEvent.", event.Name, name)
+}
+
+func (o *OrganiserBotHandler) handleListTemplates(ctx context.Context, userID int64) string {
+	templates, err := o.FirebaseConnector.ListTemplatesByOwnerID(ctx, userID)
+	if err != nil {
+		log.Println("error listing templates:", err)
+		return "Error retrieving your templates. Please try again."
+	}
+	if len(templates) == 0 {
+		return "You have no templates. Save one from an event with /saveTemplate <Event_Reference_Code> <Name>."
+	}
+
+	text := "Your templates:\n"
+	for _, template := range templates {
+		text += fmt.Sprintf("- %s: %d details, %d RSVP questions\n", template.Name, len(template.Event.EventDetails), len(template.Event.RSVPQuestions))
+	}
+	return text + "\nPick one when you use /addEvent, or remove one with /deleteTemplate <Name>."
+}
+
+func (o *OrganiserBotHandler) handleDeletingTemplate(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+	name := strings.TrimSpace(update.Message.Text)
+
+	templates, err := o.FirebaseConnector.ListTemplatesByOwnerID(ctx, update.Message.From.ID)
+	if err != nil {
+		log.Println("error listing templates:", err)
+		return "Error retrieving your templates. Please try again."
+	}
+	template := findTemplate(templates, name)
+	if template == nil {
+		return fmt.Sprintf("You have no template called '%s'. See your templates with /templates.", name)
+	}
+
+	err = o.FirebaseConnector.DeleteTemplate(ctx, template.ID)
+	if err != nil {
+		log.Println("error deleting template:", err)
+		return "Error deleting the template. Please try again."
+	}
+	return fmt.Sprintf("Template '%s' deleted.", template.Name)
+}
+
+// promptEventTemplate offers the organiser's templates at the start of /addEvent, returning false if they have none
+func (o *OrganiserBotHandler) promptEventTemplate(ctx context.Context, b *bot.Bot, chatID int64, userID int64, userState *model.UserState) bool {
+	templates, err := o.FirebaseConnector.ListTemplatesByOwnerID(ctx, userID)
+	if err != nil {
+		log.Println("error listing templates:", err)
+		return false
+	}
+	if len(templates) == 0 {
+		return false
+	}
+
+	buttons := make([]string, 0, len(templates)+2)
+	for _, template := range templates {
+		buttons = append(buttons, template.Name)
+	}
+	buttons = append(buttons, blankEventOption, "Cancel")
+	sendKeyboardPrompt(ctx, b, chatID, "Okay, let's create a new event. Start from one of your templates, or from a blank event?", buttons...)
+	userState.State = model.StateSelectEventTemplate
+	return true
+}
+
+func (o *OrganiserBotHandler) handleSelectEventTemplate(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	switch update.Message.Text {
+	case "Cancel":
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	case blankEventOption:
+		userState.State = model.StateAddingEventName
+		sendKeyboardPrompt(ctx, b, chatID, "What's the name of the event?", "Cancel")
+		return
+	}
+
+	templates, err := o.FirebaseConnector.ListTemplatesByOwnerID(ctx, update.Message.From.ID)
+	if err != nil {
+		log.Println("error listing templates:", err)
+	}
+	template := findTemplate(templates, update.Message.Text)
+	if template == nil {
+		sendKeyboardPrompt(ctx, b, chatID, "Please pick one of your templates, 'Blank event' or 'Cancel'.", "Cancel")
+		return
+	}
+
+	blueprint := template.Event
+	blueprint.HandOver(update.Message.From.ID)
+	userState.CurrentEvent = &blueprint
+	userState.State = model.StateEnteringCopyName
+	sendKeyboardPrompt(ctx, b, chatID,
+		fmt.Sprintf("Starting from '%s' with %d details and %d RSVP questions. What's the name of the event?",
+			template.Name, len(blueprint.EventDetails), len(blueprint.RSVPQuestions)),
+		"Cancel")
+}
+
+// findTemplate finds a template by name, ignoring case
+func findTemplate(templates []model.EventTemplate, name string) *model.EventTemplate {
+	for i := range templates {
+		if strings.EqualFold(templates[i].Name, strings.TrimSpace(name)) {
+			return &templates[i]
+		}
+	}
+	return nil
+}
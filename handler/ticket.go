@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"EventBot/model"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register decoders for scanned ticket photos
+	_ "image/png"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/google/uuid"
+	"github.com/makiuchi-d/gozxing"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// Prefix marking a string as an EventBot ticket, bumped if the token layout changes
+	ticketTokenPrefix = "EBT1"
+
+	// Pixel size of the ticket QR code sent to participants
+	ticketQRCodeSize = 512
+)
+
+var errInvalidTicket = errors.New("invalid ticket token")
+
+type ticketClaims struct {
+	EventID  string
+	UserID   int64
+	TicketID string
+}
+
+// ticketSecret returns the key tickets are signed with; main refuses to start without TICKET_SECRET
+func ticketSecret() []byte {
+	return []byte(os.Getenv("TICKET_SECRET"))
+}
+
+func signTicketPayload(payload string) string {
+	mac := hmac.New(sha256.New, ticketSecret())
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newTicketToken builds the signed token encoded in a participant's QR code
+func newTicketToken(eventID string, userID int64, ticketID string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s|%d|%s", eventID, userID, ticketID)))
+	return fmt.Sprintf("%s.%s.%s", ticketTokenPrefix, payload, signTicketPayload(payload))
+}
+
+// parseTicketToken verifies a ticket token's signature and returns what it claims
+func parseTicketToken(token string) (*ticketClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 || parts[0] != ticketTokenPrefix {
+		return nil, errInvalidTicket
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(signTicketPayload(parts[1]))) {
+		return nil, errInvalidTicket
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidTicket
+	}
+
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 3 {
+		return nil, errInvalidTicket
+	}
+	userID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, errInvalidTicket
+	}
+
+	return &ticketClaims{EventID: fields[0], UserID: userID, TicketID: fields[2]}, nil
+}
+
+// decodeQRCode reads the text from a QR code in an image downloaded from url
+func decodeQRCode(url string) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error decoding image: %w", err)
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := zxingqr.NewQRCodeReader().Decode(bmp, map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error reading QR code: %w", err)
+	}
+	return result.GetText(), nil
+}
+
+// sendTicket sends the participant their QR ticket for an event, issuing a ticket ID first if they have none
+func (p *ParticipantBotHandler) sendTicket(ctx context.Context, chatID int64, event *model.Event, participant *model.Participant) error {
+	signedUpEvent := findSignedUpEvent(participant, event.ID)
+	if signedUpEvent == nil {
+		return model.ErrNotSignedUp
+	}
+	if signedUpEvent.PendingApproval {
+		return model.ErrApplicationPending
+	}
+
+	// Sign-ups from before tickets existed get their ticket on first request
+	if signedUpEvent.TicketID == "" {
+		signedUpEvent.TicketID = uuid.NewString()
+		err := p.FirebaseConnector.UpdateParticipant(ctx, *participant)
+		if err != nil {
+			return fmt.Errorf("error saving ticket: %w", err)
+		}
+	}
+
+	token := newTicketToken(event.ID, participant.UserID, signedUpEvent.TicketID)
+	png, err := qrcode.Encode(token, qrcode.Medium, ticketQRCodeSize)
+	if err != nil {
+		return fmt.Errorf("error generating QR code: %w", err)
+	}
+
+	_, err = p.bot.SendPhoto(ctx, &bot.SendPhotoParams{
+		ChatID: chatID,
+		Photo:  &models.InputFileUpload{Filename: "ticket.png", Data: bytes.NewReader(png)},
+		Caption: fmt.Sprintf("Your ticket for '%s'. Show this QR code at the door to check in.\n\nTicket code: %s",
+			event.Name, token),
+	})
+	return err
+}
+
+func (p *ParticipantBotHandler) handleRequestTicket(ctx context.Context) {
+	userID := p.update.Message.From.ID
+	userState := userPBotStates[userID]
+	userState.State = model.StateIdle
+
+	eventID := p.update.Message.Text
+	text := ""
+
+	event, err := p.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		text = fmt.Sprintf("Error finding event with ID '%s'. Please check the ID and try again.", eventID)
+	} else {
+		event.ID = eventID
+		participant, err := p.FirebaseConnector.ReadParticipantByUserID(ctx, userID)
+		if err != nil || participant == nil {
+			log.Println("error reading participant:", err)
+			text = "You are not registered for this event. Please join the event first."
+		} else if err := p.sendTicket(ctx, p.update.Message.Chat.ID, event, participant); errors.Is(err, model.ErrNotSignedUp) {
+			text = "You are not registered for this event. Please join the event first."
+		} else if errors.Is(err, model.ErrApplicationPending) {
+			text = "Your application to join this event is still awaiting the organiser's approval. You'll get your ticket once it's approved."
+		} else if err != nil {
+			log.Println("error sending ticket:", err)
+			text = "Error sending your ticket. Please try again."
+		}
+	}
+
+	if text == "" {
+		return
+	}
+	_, err = p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: getParticipantMainMenuKeyboard(),
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+func (o *OrganiserBotHandler) handleScanTicketsEvent(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	eventID := update.Message.Text
+
+	isChecker, err := o.FirebaseConnector.IsEventChecker(ctx, eventID, update.Message.From.ID)
+	if err != nil {
+		log.Println("error checking event checker:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error checking permissions for event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	if !isChecker {
+		userState.State = model.StateIdle
+		return "Only the event owner, coowners or designated checkers can scan tickets."
+	}
+
+	event, err := o.FirebaseConnector.ReadEvent(ctx, eventID)
+	if err != nil {
+		log.Println("error reading event:", err)
+		userState.State = model.StateIdle
+		return fmt.Sprintf("Error reading event with ID '%s'. Please check the ID and try again.", eventID)
+	}
+	event.ID = eventID
+
+	if blockedText := checkInBlockedText(event); blockedText != "" {
+		userState.State = model.StateIdle
+		return blockedText
+	}
+
+	userState.CurrentEvent = event
+	userState.State = model.StateScanningTickets
+	return fmt.Sprintf("Scanning tickets for '%s'. Send a photo of a ticket QR code or paste the ticket code. Send 'Done' when you're finished.", event.Name)
+}
+
+func (o *OrganiserBotHandler) handleScanningTickets(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	if update.Message.Text == "Done" || update.Message.Text == "Cancel" {
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		return "Stopped scanning tickets."
+	}
+
+	token := update.Message.Text
+	if update.Message.Photo != nil {
+		largestPhoto := update.Message.Photo[len(update.Message.Photo)-1]
+		url, err := o.ImageService.ConvertFileIDToURL(ctx, largestPhoto.FileID)
+		if err != nil {
+			log.Println("error getting ticket photo:", err)
+			return "Couldn't download that photo. Please try again or paste the ticket code."
+		}
+		token, err = decodeQRCode(url)
+		if err != nil {
+			log.Println("error decoding ticket photo:", err)
+			return "No QR code found in that photo. Please try again with the code filling more of the frame, or paste the ticket code."
+		}
+	}
+
+	return o.checkInTicket(ctx, userState.CurrentEvent, token, update.Message.From.ID)
+}
+
+// checkInTicket validates a scanned ticket token for the event and checks its holder in on behalf of scannerID
+func (o *OrganiserBotHandler) checkInTicket(ctx context.Context, event *model.Event, token string, scannerID int64) string {
+	claims, err := parseTicketToken(token)
+	if err != nil {
+		return "❌ Invalid ticket. This ticket is forged or damaged."
+	}
+	if claims.EventID != event.ID {
+		return "❌ This ticket is for a different event."
+	}
+
+	participant, err := o.FirebaseConnector.ReadParticipantByUserID(ctx, claims.UserID)
+	if err != nil {
+		log.Println("error reading participant:", err)
+		return "Error looking up the ticket holder. Please try again."
+	}
+	if participant == nil {
+		return "❌ The ticket holder is not registered for this event."
+	}
+
+	signedUpEvent := findSignedUpEvent(participant, event.ID)
+	if signedUpEvent == nil || signedUpEvent.TicketID != claims.TicketID {
+		return "❌ This ticket is no longer valid."
+	}
+
+	now := time.Now()
+	_, err = o.FirebaseConnector.CheckInParticipant(ctx, participant.ID, event.ID, now, scannerID)
+	if errors.Is(err, model.ErrAlreadyCheckedIn) {
+		return fmt.Sprintf("⚠️ %s has already checked in (at %s).", participant.Name,
+			signedUpEvent.CheckedInAt.In(o.organiserLocation(ctx, event.UserID)).Format("15:04"))
+	} else if errors.Is(err, model.ErrNotSignedUp) {
+		return "❌ This ticket is no longer valid."
+	} else if errors.Is(err, model.ErrApplicationPending) {
+		return fmt.Sprintf("❌ %s's application hasn't been approved yet.", participant.Name)
+	} else if err != nil {
+		log.Println("error checking in participant:", err)
+		return "Error checking in the ticket holder. Please try again."
+	}
+
+	notifyCheckIn(event.ID)
+
+	err = o.FirebaseConnector.CreateAuditEntry(ctx, model.AuditEntry{
+		EventID:      event.ID,
+		Action:       model.AuditActionTicketCheckIn,
+		ActorID:      scannerID,
+		TargetUserID: participant.UserID,
+		At:           now.UTC(),
+	})
+	if err != nil {
+		log.Println("error recording audit entry:", err)
+	}
+	return fmt.Sprintf("✅ %s checked in.", participant.Name) + guestCheckInHint(event, participant)
+}
+
+func (o *OrganiserBotHandler) handleAddingChecker(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, checkerID, errText := parseEventAndUserID(update.Message.Text)
+	if errText != "" {
+		return errText
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionManageTeam)
+	if err != nil {
+		return "Error checking event ownership. Please try again."
+	}
+	if !allowed {
+		return "You don't have permission to add checkers to this event."
+	}
+
+	err = o.FirebaseConnector.AddChecker(ctx, eventID, checkerID)
+	if err != nil {
+		return fmt.Sprintf("Error adding checker: %v", err)
+	}
+	return fmt.Sprintf("User %d can now scan tickets for event %s using /scanTickets.", checkerID, eventID)
+}
+
+func (o *OrganiserBotHandler) handleRemovingChecker(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	userState.State = model.StateIdle
+
+	eventID, checkerID, errText := parseEventAndUserID(update.Message.Text)
+	if errText != "" {
+		return errText
+	}
+
+	allowed, err := o.FirebaseConnector.HasEventPermission(ctx, eventID, update.Message.From.ID, model.PermissionManageTeam)
+	if err != nil {
+		return "Error checking event ownership. Please try again."
+	}
+	if !allowed {
+		return "You don't have permission to remove checkers from this event."
+	}
+
+	err = o.FirebaseConnector.RemoveChecker(ctx, eventID, checkerID)
+	if err != nil {
+		return fmt.Sprintf("Error removing checker: %v", err)
+	}
+	return fmt.Sprintf("User %d can no longer scan tickets for event %s.", checkerID, eventID)
+}
+
+// parseEventAndUserID parses "EVENT_REF_CODE USER_ID" input; errText is set on failure
+func parseEventAndUserID(text string) (string, int64, string) {
+	parts := strings.Fields(text)
+	if len(parts) != 2 {
+		return "", 0, "Invalid format. Please use: EVENT_REF_CODE USER_ID\nExample: ABC123 123456789"
+	}
+
+	userID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, "Invalid User ID. Please provide a valid Telegram User ID (numeric)."
+	}
+	return parts[0], userID, ""
+}
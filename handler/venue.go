@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// Bounds for the distance from the venue participants may check in from, in metres
+	minCheckInRadius = 50
+	maxCheckInRadius = 5000
+
+	earthRadiusMetres = 6371000
+)
+
+// venueFromMessage reads a venue from a Telegram venue or location message, or returns nil if the message has neither
+func venueFromMessage(message *models.Message) *model.Venue {
+	switch {
+	case message.Venue != nil:
+		return &model.Venue{
+			Name:    message.Venue.Title,
+			Address: message.Venue.Address,
+			Coordinates: &model.Coordinates{
+				Latitude:  message.Venue.Location.Latitude,
+				Longitude: message.Venue.Location.Longitude,
+			},
+		}
+	case message.Location != nil:
+		return &model.Venue{
+			Coordinates: &model.Coordinates{
+				Latitude:  message.Location.Latitude,
+				Longitude: message.Location.Longitude,
+			},
+		}
+	}
+	return nil
+}
+
+// parseVenueText reads a typed venue: the name on the first line and the address on the following ones
+func parseVenueText(text string) *model.Venue {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return &model.Venue{Name: lines[0], Address: strings.Join(lines[1:], ", ")}
+}
+
+// requiresCheckInLocation reports whether participants must share their location to check in
+func requiresCheckInLocation(event *model.Event) bool {
+	return event.Venue != nil && event.Venue.Coordinates != nil && event.CheckInRadius > 0
+}
+
+// distanceMetres returns the great-circle distance between two coordinates
+func distanceMetres(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMetres * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// parseCheckInRadius reads a radius in metres, with "skip" turning the location check off; errText is set on failure
+func parseCheckInRadius(text string) (int, string) {
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "skip") {
+		return 0, ""
+	}
+
+	radius, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(text, "m")))
+	if err != nil || radius < minCheckInRadius || radius > maxCheckInRadius {
+		return 0, fmt.Sprintf("Please enter a radius between %d and %d metres (e.g., 200), or 'skip'.", minCheckInRadius, maxCheckInRadius)
+	}
+	return radius, ""
+}
+
+// formatVenue renders a venue's name and address on separate lines
+func formatVenue(venue *model.Venue) string {
+	var lines []string
+	if venue.Name != "" {
+		lines = append(lines, venue.Name)
+	}
+	if venue.Address != "" {
+		lines = append(lines, venue.Address)
+	}
+	if len(lines) == 0 && venue.Coordinates != nil {
+		lines = append(lines, fmt.Sprintf("%.5f, %.5f", venue.Coordinates.Latitude, venue.Coordinates.Longitude))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeVenue summarises an event's venue and check-in radius for organisers
+func describeVenue(event *model.Event) string {
+	if event.Venue == nil {
+		return "No venue set."
+	}
+
+	text := "Venue:\n" + formatVenue(event.Venue) + "\n"
+	if event.Venue.Coordinates == nil {
+		return text + "No map location. Participants can check in from anywhere."
+	}
+	text += fmt.Sprintf("Map location: %.5f, %.5f\n", event.Venue.Coordinates.Latitude, event.Venue.Coordinates.Longitude)
+	if event.CheckInRadius > 0 {
+		text += fmt.Sprintf("Participants must be within %d metres to check in.", event.CheckInRadius)
+	} else {
+		text += "Participants can check in from anywhere."
+	}
+	return text
+}
+
+// sendVenue sends the venue as a native Telegram venue or location so it opens in a maps app; venues without coordinates are skipped
+func sendVenue(ctx context.Context, b *bot.Bot, chatID int64, venue *model.Venue) error {
+	if venue == nil || venue.Coordinates == nil {
+		return nil
+	}
+
+	// Telegram venues need both a title and an address; otherwise fall back to a plain pin
+	if venue.Name != "" && venue.Address != "" {
+		_, err := b.SendVenue(ctx, &bot.SendVenueParams{
+			ChatID:    chatID,
+			Latitude:  venue.Coordinates.Latitude,
+			Longitude: venue.Coordinates.Longitude,
+			Title:     venue.Name,
+			Address:   venue.Address,
+		})
+		return err
+	}
+
+	_, err := b.SendLocation(ctx, &bot.SendLocationParams{
+		ChatID:    chatID,
+		Latitude:  venue.Coordinates.Latitude,
+		Longitude: venue.Coordinates.Longitude,
+	})
+	return err
+}
+
+// sendKeyboardPrompt sends text with a one-time reply keyboard of the given buttons, one per row
+func sendKeyboardPrompt(ctx context.Context, b *bot.Bot, chatID int64, text string, buttons ...string) {
+	keyboard := make([][]models.KeyboardButton, 0, len(buttons))
+	for _, button := range buttons {
+		keyboard = append(keyboard, []models.KeyboardButton{{Text: button}})
+	}
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+		ReplyMarkup: &models.ReplyKeyboardMarkup{
+			Keyboard:        keyboard,
+			ResizeKeyboard:  true,
+			OneTimeKeyboard: true,
+		},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// cancelEventCreation abandons /addEvent and shows the main menu
+func cancelEventCreation(ctx context.Context, b *bot.Bot, chatID int64, userState *model.UserState) {
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        "Event creation cancelled. What would you like to do next?",
+		ReplyMarkup: getOrganizerMainMenuKeyboard(),
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+	userState.State = model.StateIdle
+	userState.CurrentEvent = nil
+}
+
+// promptEventVenue asks for the venue during /addEvent
+func (o *OrganiserBotHandler) promptEventVenue(ctx context.Context, b *bot.Bot, chatID int64, userState *model.UserState) {
+	sendKeyboardPrompt(ctx, b, chatID,
+		"Where is the event? Send a Telegram venue or location (📎 → Location), or type the venue name with the address on the next line.\n\n"+
+			"Send 'skip' if there isn't one.",
+		"skip", "Cancel")
+	userState.State = model.StateAddingEventVenue
+}
+
+// promptEventCheckInRadius asks during /addEvent whether check-in should require being at the venue
+func (o *OrganiserBotHandler) promptEventCheckInRadius(ctx context.Context, b *bot.Bot, chatID int64, userState *model.UserState) {
+	sendKeyboardPrompt(ctx, b, chatID,
+		fmt.Sprintf("Should participants have to be at the venue to check in? Send a radius in metres (%d-%d, e.g. 200), "+
+			"or 'skip' to let them check in from anywhere.", minCheckInRadius, maxCheckInRadius),
+		"skip", "Cancel")
+	userState.State = model.StateAddingEventCheckInRadius
+}
+
+// promptEventEDM moves /addEvent on to asking for the EDM
+func (o *OrganiserBotHandler) promptEventEDM(ctx context.Context, b *bot.Bot, chatID int64, userState *model.UserState) {
+	sendKeyboardPrompt(ctx, b, chatID, "Great! Now, please send me the EDM for the event.", "Cancel")
+	userState.State = model.StateAddingEventPicture
+}
+
+func (o *OrganiserBotHandler) handleAddingEventVenue(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	switch strings.ToLower(update.Message.Text) {
+	case "cancel":
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	case "skip":
+		o.promptEventEDM(ctx, b, chatID, userState)
+		return
+	}
+
+	venue := venueFromMessage(update.Message)
+	if venue == nil {
+		venue = parseVenueText(update.Message.Text)
+		if venue == nil {
+			sendKeyboardPrompt(ctx, b, chatID, "Please send a venue, a location or the venue name and address, or 'skip'.", "skip", "Cancel")
+			return
+		}
+
+		// A typed venue has no map pin, so there is no radius to ask about
+		userState.CurrentEvent.Venue = venue
+		o.promptEventEDM(ctx, b, chatID, userState)
+		return
+	}
+
+	userState.CurrentEvent.Venue = venue
+	if venue.Name == "" {
+		sendKeyboardPrompt(ctx, b, chatID,
+			"Got the location. What's the venue called? Send the name with the address on the next line, or 'skip'.",
+			"skip", "Cancel")
+		userState.State = model.StateAddingEventVenueName
+		return
+	}
+	o.promptEventCheckInRadius(ctx, b, chatID, userState)
+}
+
+func (o *OrganiserBotHandler) handleAddingEventVenueName(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	if update.Message.Text == "Cancel" {
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	}
+
+	if !strings.EqualFold(update.Message.Text, "skip") {
+		named := parseVenueText(update.Message.Text)
+		if named == nil {
+			sendKeyboardPrompt(ctx, b, chatID, "Please send the venue name with the address on the next line, or 'skip'.", "skip", "Cancel")
+			return
+		}
+		userState.CurrentEvent.Venue.Name = named.Name
+		userState.CurrentEvent.Venue.Address = named.Address
+	}
+	o.promptEventCheckInRadius(ctx, b, chatID, userState)
+}
+
+func (o *OrganiserBotHandler) handleAddingEventCheckInRadius(ctx context.Context, b *bot.Bot, update *models.Update, userState *model.UserState) {
+	chatID := update.Message.Chat.ID
+	if update.Message.Text == "Cancel" {
+		cancelEventCreation(ctx, b, chatID, userState)
+		return
+	}
+
+	radius, errText := parseCheckInRadius(update.Message.Text)
+	if errText != "" {
+		sendKeyboardPrompt(ctx, b, chatID, errText, "skip", "Cancel")
+		return
+	}
+
+	userState.CurrentEvent.CheckInRadius = radius
+	o.promptEventEDM(ctx, b, chatID, userState)
+}
+
+func (o *OrganiserBotHandler) handleEditEventVenue(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	event := userState.CurrentEvent
+
+	switch {
+	case strings.EqualFold(update.Message.Text, "remove"):
+		event.Venue = nil
+		event.CheckInRadius = 0
+		return o.saveEditedVenue(ctx, userState)
+	case strings.EqualFold(update.Message.Text, "keep") && event.Venue != nil && event.Venue.Coordinates != nil:
+		userState.State = model.StateEditEventCheckInRadius
+		return fmt.Sprintf("Send the new check-in radius in metres (%d-%d), or 'skip' to let participants check in from anywhere.",
+			minCheckInRadius, maxCheckInRadius)
+	}
+
+	venue := venueFromMessage(update.Message)
+	if venue == nil {
+		// Typed text renames the venue and keeps any map location already set
+		venue = parseVenueText(update.Message.Text)
+		if venue == nil {
+			return "Please send a venue, a location or the venue name and address, 'keep' to keep the current location, or 'remove' to clear it."
+		}
+		if event.Venue != nil {
+			venue.Coordinates = event.Venue.Coordinates
+		}
+		event.Venue = venue
+		return o.saveEditedVenue(ctx, userState)
+	}
+
+	event.Venue = venue
+	if venue.Name == "" {
+		userState.State = model.StateEditEventVenueName
+		return "Got the location. What's the venue called? Send the name with the address on the next line, or 'skip'."
+	}
+	userState.State = model.StateEditEventCheckInRadius
+	return fmt.Sprintf("Venue updated. Send a check-in radius in metres (%d-%d), or 'skip' to let participants check in from anywhere.",
+		minCheckInRadius, maxCheckInRadius)
+}
+
+func (o *OrganiserBotHandler) handleEditEventVenueName(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	if !strings.EqualFold(update.Message.Text, "skip") {
+		named := parseVenueText(update.Message.Text)
+		if named == nil {
+			return "Please send the venue name with the address on the next line, or 'skip'."
+		}
+		userState.CurrentEvent.Venue.Name = named.Name
+		userState.CurrentEvent.Venue.Address = named.Address
+	}
+
+	userState.State = model.StateEditEventCheckInRadius
+	return fmt.Sprintf("Send a check-in radius in metres (%d-%d), or 'skip' to let participants check in from anywhere.",
+		minCheckInRadius, maxCheckInRadius)
+}
+
+func (o *OrganiserBotHandler) handleEditEventCheckInRadius(ctx context.Context, update *models.Update, userState *model.UserState) string {
+	radius, errText := parseCheckInRadius(update.Message.Text)
+	if errText != "" {
+		return errText
+	}
+
+	userState.CurrentEvent.CheckInRadius = radius
+	return o.saveEditedVenue(ctx, userState)
+}
+
+// saveEditedVenue stores the venue changes made through /editEvent and ends the edit
+func (o *OrganiserBotHandler) saveEditedVenue(ctx context.Context, userState *model.UserState) string {
+	event := userState.CurrentEvent
+	oldVenue := userState.LastQuestion
+
+	err := o.FirebaseConnector.UpdateEvent(ctx, event.ID, *event)
+	if err != nil {
+		log.Println("error updating event:", err)
+		userState.State = model.StateIdle
+		userState.CurrentEvent = nil
+		userState.LastQuestion = ""
+		return "Error updating event venue. Please try again."
+	}
+
+	// Only the check-in radius may have changed, which participants don't need to hear about
+	var change string
+	if newVenue := describeVenueForChange(event.Venue); newVenue != oldVenue {
+		change = describeEventChange("Venue", oldVenue, newVenue)
+	}
+	venue, radius := event.Venue, event.CheckInRadius
+	seriesText, occurrences := o.applyToLaterOccurrences(ctx, userState, func(event *model.Event) {
+		event.Venue = venue
+		event.CheckInRadius = radius
+	})
+	return o.offerChangeNotice(userState, event, occurrences, "Venue saved."+seriesText+"\n\n"+describeVenue(event), change)
+}
+
+// promptCheckInLocation asks the participant to share their location with a request_location button
+func (p *ParticipantBotHandler) promptCheckInLocation(ctx context.Context) {
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: p.update.Message.Chat.ID,
+		Text:   "This event checks that you are at the venue. Please tap the button below to share your location.",
+		ReplyMarkup: &models.ReplyKeyboardMarkup{
+			Keyboard: [][]models.KeyboardButton{
+				{
+					{Text: "📍 Share my location", RequestLocation: true},
+				},
+				{
+					{Text: "Cancel"},
+				},
+			},
+			ResizeKeyboard:  true,
+			OneTimeKeyboard: true,
+		},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
+
+// handleCheckInLocation verifies the shared location is within the event's radius before asking for the check-in code
+func (p *ParticipantBotHandler) handleCheckInLocation(ctx context.Context) {
+	userID := p.update.Message.From.ID
+	userState := userPBotStates[userID]
+	event := userState.CurrentEvent
+
+	if p.update.Message.Text != "Cancel" && (p.update.Message.Location == nil || p.update.Message.ForwardOrigin != nil) {
+		// Only a freshly shared location counts, not text or a forwarded one
+		p.promptCheckInLocation(ctx)
+		return
+	}
+
+	text := "Check-in cancelled."
+	userState.State = model.StateIdle
+	if location := p.update.Message.Location; location != nil {
+		venue := event.Venue.Coordinates
+		distance := distanceMetres(location.Latitude, location.Longitude, venue.Latitude, venue.Longitude)
+		if distance <= float64(event.CheckInRadius) {
+			userState.State = model.StateEnteringCheckInCode
+			text = "Location confirmed. Please enter the 4-digit check-in code provided by the event organizer:"
+		} else {
+			text = fmt.Sprintf("You appear to be about %d metres from the venue. You need to be within %d metres to check in.",
+				int(math.Round(distance)), event.CheckInRadius)
+		}
+	}
+	if userState.State == model.StateIdle {
+		userState.CurrentEvent = nil
+	}
+
+	_, err := p.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      p.update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: &models.ReplyKeyboardRemove{RemoveKeyboard: true},
+	})
+	if err != nil {
+		log.Println("error sending message:", err)
+	}
+}
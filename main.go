@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // Embed timezone data so organiser timezones resolve on any host
 
 	"github.com/go-telegram/bot"
 	"github.com/joho/godotenv"
@@ -30,6 +31,11 @@ func main() {
 		log.Fatal().Msg("PARTICIPANT_BOT_TOKEN environment variable not set")
 	}
 
+	// Tickets are signed with this key, so without it anyone could forge one
+	if os.Getenv("TICKET_SECRET") == "" {
+		log.Fatal().Msg("TICKET_SECRET environment variable not set")
+	}
+
 	firebaseConnector, err := InitializeFirebase(context.Background())
 	if err != nil {
 		log.Fatal().Err(err).Msg("Error initializing Firebase")
@@ -64,6 +70,12 @@ func main() {
 	go b.Start(ctx)
 	go c.Start(ctx)
 
+	// Send scheduled blasts in the background, picking up any that were pending before a restart
+	go organiserBotHandler.RunBlastScheduler(ctx, b)
+
+	// Remove deleted events from participants once their restore window closes
+	go organiserBotHandler.RunEventPurger(ctx, b)
+
 	<-ctx.Done()
 	log.Info().Msg("Bots stopped")
 }
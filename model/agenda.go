@@ -0,0 +1,69 @@
+package model
+
+import (
+	"slices"
+	"sort"
+)
+
+// AgendaSession is one slot of an event's agenda, such as a talk or breakout, that participants sign up for separately
+type AgendaSession struct {
+	ID        string   `firestore:"id"`
+	Title     string   `firestore:"title"`
+	Start     string   `firestore:"start"` // "15:04" on the event date
+	End       string   `firestore:"end"`
+	Room      string   `firestore:"room"`
+	Speaker   string   `firestore:"speaker"`
+	Capacity  int      `firestore:"capacity"`  // 0 for no limit
+	Attendees []string `firestore:"attendees"` // Participant IDs signed up for the session
+	CheckedIn []string `firestore:"checkedIn"` // Participant IDs checked in at the session
+}
+
+// IsFull reports whether the session has no places left
+func (s *AgendaSession) IsFull() bool {
+	return s.Capacity > 0 && len(s.Attendees) >= s.Capacity
+}
+
+// Overlaps reports whether the two sessions run at the same time
+func (s *AgendaSession) Overlaps(other *AgendaSession) bool {
+	// Times are zero-padded "15:04", so they compare as strings
+	return s.Start < other.End && other.Start < s.End
+}
+
+// FindAgendaSession returns the session with the given ID, or nil if the agenda has none
+func (e *Event) FindAgendaSession(sessionID string) *AgendaSession {
+	for i := range e.Agenda {
+		if e.Agenda[i].ID == sessionID {
+			return &e.Agenda[i]
+		}
+	}
+	return nil
+}
+
+// ClashingSession returns another session the participant signed up for that overlaps session, or nil if there is none
+func (e *Event) ClashingSession(session *AgendaSession, participantID string) *AgendaSession {
+	for i := range e.Agenda {
+		other := &e.Agenda[i]
+		if other.ID != session.ID && slices.Contains(other.Attendees, participantID) && other.Overlaps(session) {
+			return other
+		}
+	}
+	return nil
+}
+
+// SortAgenda orders the agenda by start time so sessions keep their numbers for participants
+func (e *Event) SortAgenda() {
+	sort.SliceStable(e.Agenda, func(i, j int) bool {
+		if e.Agenda[i].Start != e.Agenda[j].Start {
+			return e.Agenda[i].Start < e.Agenda[j].Start
+		}
+		return e.Agenda[i].Title < e.Agenda[j].Title
+	})
+}
+
+// LeaveAgenda takes the participant off every session of the agenda
+func (e *Event) LeaveAgenda(participantID string) {
+	for i := range e.Agenda {
+		e.Agenda[i].Attendees = slices.DeleteFunc(e.Agenda[i].Attendees, func(id string) bool { return id == participantID })
+		e.Agenda[i].CheckedIn = slices.DeleteFunc(e.Agenda[i].CheckedIn, func(id string) bool { return id == participantID })
+	}
+}
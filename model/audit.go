@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// AuditAction names the kind of event recorded in an event's audit log
+type AuditAction string
+
+const (
+	AuditActionCheckInFailed  AuditAction = "checkin_failed"
+	AuditActionCheckInLockout AuditAction = "checkin_lockout"
+	AuditActionManualCheckIn  AuditAction = "checkin_manual"
+	AuditActionTicketCheckIn  AuditAction = "checkin_ticket"
+	AuditActionCheckInUndone  AuditAction = "checkin_undone"
+	AuditActionRemoved        AuditAction = "participant_removed"
+	AuditActionApproved       AuditAction = "application_approved"
+	AuditActionRejected       AuditAction = "application_rejected"
+	AuditActionOwnerChanged   AuditAction = "ownership_transferred"
+)
+
+type AuditEntry struct {
+	ID           string      `firestore:"id"`
+	EventID      string      `firestore:"eventID"`
+	Action       AuditAction `firestore:"action"`
+	ActorID      int64       `firestore:"actorID"`      // Telegram user who performed the action
+	TargetUserID int64       `firestore:"targetUserID"` // Telegram user the action was about, if any
+	Details      string      `firestore:"details"`
+	At           time.Time   `firestore:"at"`
+}
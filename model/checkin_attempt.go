@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// CheckInAttempt tracks a user's failed check-in code attempts for one event
+type CheckInAttempt struct {
+	EventID      string    `firestore:"eventID"`
+	UserID       int64     `firestore:"userid"`
+	FailedCount  int       `firestore:"failedCount"`
+	LastFailedAt time.Time `firestore:"lastFailedAt"`
+	LockedUntil  time.Time `firestore:"lockedUntil"`
+}
+
+// EventCheckInGuard counts failed check-in attempts across all users of an event within a time window
+type EventCheckInGuard struct {
+	EventID        string    `firestore:"eventID"`
+	WindowStart    time.Time `firestore:"windowStart"`
+	WindowFailures int       `firestore:"windowFailures"`
+	LastAlertAt    time.Time `firestore:"lastAlertAt"`
+}
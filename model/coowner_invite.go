@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// CoownerInvite is a one-time organiser bot link that makes whoever opens it a coowner of an event
+type CoownerInvite struct {
+	Token     string      `firestore:"token"` // Also the document ID and the deep link parameter
+	EventID   string      `firestore:"eventID"`
+	Role      CoownerRole `firestore:"role"`
+	CreatedBy int64       `firestore:"createdBy"`
+	CreatedAt time.Time   `firestore:"createdAt"`
+	ExpiresAt time.Time   `firestore:"expiresAt"`
+	UsedBy    int64       `firestore:"usedBy"` // Zero until accepted
+	UsedAt    time.Time   `firestore:"usedAt"`
+}
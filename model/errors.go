@@ -5,4 +5,31 @@ import "errors"
 var (
 	ErrParticipantDoesNotExist = errors.New("participant do not exist")
 	ErrEventDoesNotExist       = errors.New("event do not exist")
+	ErrBlastDoesNotExist       = errors.New("scheduled blast do not exist")
+	ErrBlastNotPending         = errors.New("scheduled blast is no longer pending")
+	ErrNotSignedUp             = errors.New("participant is not signed up for the event")
+	ErrAlreadyCheckedIn        = errors.New("participant is already checked in")
+	ErrNotCheckedIn            = errors.New("participant is not checked in")
+	ErrAlreadyBanned           = errors.New("user is already banned")
+	ErrNotBanned               = errors.New("user is not banned")
+	ErrApplicationPending      = errors.New("participant's application is awaiting approval")
+	ErrNoApplication           = errors.New("participant has no pending application for the event")
+	ErrInviteDoesNotExist      = errors.New("invite do not exist")
+	ErrInviteRevoked           = errors.New("invite has been revoked")
+	ErrInviteExpired           = errors.New("invite has expired")
+	ErrInviteUsedUp            = errors.New("invite has no uses left")
+	ErrAlreadyCoowner          = errors.New("user is already on the event's team")
+	ErrNotPrimaryOwner         = errors.New("user is not the event's primary owner")
+	ErrNotCoowner              = errors.New("user is not a coowner of the event")
+	ErrNoTransferPending       = errors.New("no ownership transfer is pending for the user")
+	ErrInvalidStatusChange     = errors.New("event cannot move to that status")
+	ErrEventDeleted            = errors.New("event has been deleted")
+	ErrEventNotDeleted         = errors.New("event has not been deleted")
+	ErrRestoreWindowClosed     = errors.New("event can no longer be restored")
+	ErrSessionDoesNotExist     = errors.New("agenda session do not exist")
+	ErrSessionFull             = errors.New("agenda session is full")
+	ErrSessionClash            = errors.New("agenda session overlaps another session the participant signed up for")
+	ErrNotInSession            = errors.New("participant is not signed up for the agenda session")
+	ErrEventFull               = errors.New("event has reached its capacity")
+	ErrTooManyGuests           = errors.New("participant is bringing more guests than the event allows")
 )
@@ -11,20 +11,86 @@ const (
 	QuestionTypeMCQ
 	QuestionTypeMultiSelect
 	QuestionTypeShortAnswer
+	QuestionTypeRating // 1-5 scale
+	QuestionTypeNumber
 )
 
 type Event struct {
-	ID            string         `firestore:"id"`
-	UserID        int64          `firestore:"userid"`   // Primary owner
-	Coowners      []int64        `firestore:"coowners"` // List of coowner user IDs
-	Name          string         `firestore:"name"`
-	EDMFileID     string         `firestore:"edmFileID"`
-	EDMFileURL    string         `firestore:"edmFileURL"`
-	EventDate     time.Time      `firestore:"eventDate"`
-	EventDetails  []QnA          `firestore:"eventDetails"`
-	RSVPQuestions []RSVPQuestion `firestore:"rsvpQuestions"`
-	Participants  []string       `firestore:"participants"` //list of participants by id
-	CheckInCode   string         `firestore:"checkInCode"`  // New field for the check-in code set by organizer
+	ID            string                 `firestore:"id"`
+	UserID        int64                  `firestore:"userid"`       // Primary owner
+	Coowners      []int64                `firestore:"coowners"`     // List of coowner user IDs
+	CoownerRoles  map[string]CoownerRole `firestore:"coownerRoles"` // Role of each coowner, keyed by user ID
+	PendingOwner  int64                  `firestore:"pendingOwner"` // Coowner nominated to take over the event, 0 if none
+	Name          string                 `firestore:"name"`
+	EDMFileID     string                 `firestore:"edmFileID"`
+	EDMFileURL    string                 `firestore:"edmFileURL"`
+	EventDate     time.Time              `firestore:"eventDate"`
+	EventDetails  []QnA                  `firestore:"eventDetails"`
+	RSVPQuestions []RSVPQuestion         `firestore:"rsvpQuestions"`
+	Participants  []string               `firestore:"participants"` //list of participants by id
+	CheckInCode   string                 `firestore:"checkInCode"`  // New field for the check-in code set by organizer
+	Checkers      []int64                `firestore:"checkers"`     // Users allowed to scan tickets at the door
+
+	// Rotating check-in codes; when CheckInCodePeriod is set the code is a TOTP of CheckInCodeSecret
+	CheckInCodeSecret string `firestore:"checkInCodeSecret"`
+	CheckInCodePeriod int    `firestore:"checkInCodePeriod"` // Seconds each code is valid for, 0 for a static code
+
+	// Participants may only check in inside these windows; when empty check-in is open on the event date
+	CheckInWindows []CheckInWindow `firestore:"checkInWindows"`
+
+	// When CheckInRadius is set participants must share a location within that many metres of the venue to check in
+	Venue         *Venue `firestore:"venue"`
+	CheckInRadius int    `firestore:"checkInRadius"`
+
+	// When RequiresApproval is set joining creates an application that an owner or coowner must approve
+	RequiresApproval bool     `firestore:"requiresApproval"`
+	Applicants       []string `firestore:"applicants"` // Participant IDs awaiting approval
+
+	// When InviteOnly is set participants can only join through one of the event's invites
+	InviteOnly bool `firestore:"inviteOnly"`
+
+	// Status is empty for events created before statuses existed, use CurrentStatus to read it
+	Status          EventStatus `firestore:"status"`
+	CancelReason    string      `firestore:"cancelReason"`
+	StatusChangedAt time.Time   `firestore:"statusChangedAt"`
+
+	// Deleted events are hidden and can be restored until PurgeAt, when they are removed from participants for good
+	DeletedAt     time.Time `firestore:"deletedAt"`
+	DeletedBy     int64     `firestore:"deletedBy"`
+	PurgeAt       time.Time `firestore:"purgeAt"`
+	NotifyOnPurge bool      `firestore:"notifyOnPurge"` // Tell participants once the event is removed
+
+	// Occurrences of a recurring series share details and RSVP questions
+	SeriesID    string `firestore:"seriesID"`
+	SeriesIndex int    `firestore:"seriesIndex"` // 1-based position in the series
+	StartTime   string `firestore:"startTime"`   // "15:04" in the organiser's timezone, if the event has a set start
+
+	// Sessions participants can pick from, ordered by start time
+	Agenda []AgendaSession `firestore:"agenda"`
+
+	// Participants may register up to MaxGuests guests each; guests count toward Capacity, which is 0 for no limit
+	MaxGuests   int            `firestore:"maxGuests"`
+	GuestRSVP   bool           `firestore:"guestRSVP"` // Ask the RSVP questions for each guest too
+	Capacity    int            `firestore:"capacity"`
+	GuestCounts map[string]int `firestore:"guestCounts"` // Number of guests of each participant, keyed by participant ID
+}
+
+// Venue is where an event takes place
+type Venue struct {
+	Name        string       `firestore:"name"`
+	Address     string       `firestore:"address"`
+	Coordinates *Coordinates `firestore:"coordinates"` // Optional map pin
+}
+
+type Coordinates struct {
+	Latitude  float64 `firestore:"latitude"`
+	Longitude float64 `firestore:"longitude"`
+}
+
+// CheckInWindow is a period during which participants may check in to an event
+type CheckInWindow struct {
+	OpensAt  time.Time `firestore:"opensAt"`
+	ClosesAt time.Time `firestore:"closesAt"`
 }
 
 type QnA struct {
@@ -49,9 +115,10 @@ type RSVPAnswer struct {
 }
 
 type Participant struct {
-	ID     string `firestore:"id"`
-	UserID int64  `firestore:"userid"`
-	Name   string `firestore:"name"`
+	ID       string `firestore:"id"`
+	UserID   int64  `firestore:"userid"`
+	Name     string `firestore:"name"`
+	Username string `firestore:"username"` // Telegram @username, may be empty
 
 	SignedUpEvents []SignedUpEvent `firestore:"signedUpEvents"` //list of events by id
 }
@@ -61,6 +128,18 @@ type SignedUpEvent struct {
 	PersonalNotes string       `firestore:"personalNotes"`
 	CheckedIn     bool         `firestore:"checkedIn"`
 	RSVPAnswers   []RSVPAnswer `firestore:"rsvpAnswers"`
+	JoinedAt      time.Time    `firestore:"joinedAt"`
+	CheckedInAt   time.Time    `firestore:"checkedInAt"`
+	CheckedInBy   int64        `firestore:"checkedInBy"` // User who checked them in; their own ID for self check-in
+	TicketID      string       `firestore:"ticketID"`    // Random ID embedded in the signed ticket token
+
+	PendingApproval bool `firestore:"pendingApproval"` // Applied to an approval-required event and not yet approved
+
+	// Invite the participant joined with, if any; the label is copied so it survives the invite
+	InviteToken string `firestore:"inviteToken"`
+	InviteLabel string `firestore:"inviteLabel"`
+
+	Guests []Guest `firestore:"guests"` // People the participant registered along with themselves
 }
 
 const (
@@ -98,6 +177,140 @@ const (
 	StateAddCoownerToEditedEvent
 	StateRemovingCoowner
 
+	// Scheduled blast states
+	StateSelectBlastTiming
+	StateSchedulingBlastTime
+	StateListScheduledBlasts
+	StateEditScheduledBlast
+	StateSelectScheduledBlastEditOption
+	StateEditScheduledBlastMessage
+	StateEditScheduledBlastTime
+	StateCancelScheduledBlast
+	StateSettingTimezone
+
+	// Export states
+	StateExportParticipants
+	StateSelectExportFormat
+
+	// RSVP analytics states
+	StateRSVPSummary
+
+	// Ticket states
+	StateScanTicketsEvent
+	StateScanningTickets
+	StateAddingChecker
+	StateRemovingChecker
+
+	// Rotating check-in code states
+	StateSettingCheckInCodePeriod
+	StateShowCheckInCode
+	StateLiveCheckInCode
+
+	// Check-in window states
+	StateSettingCheckInWindowsEvent
+	StateEnteringCheckInWindows
+
+	// Manual check-in states
+	StateManualCheckIn
+	StateSelectManualCheckInMatch
+	StateUndoCheckIn
+	StateSelectUndoCheckInMatch
+
+	// Venue states
+	StateAddingEventVenue
+	StateAddingEventVenueName
+	StateAddingEventCheckInRadius
+	StateEditEventVenue
+	StateEditEventVenueName
+	StateEditEventCheckInRadius
+
+	// Check-in dashboard states
+	StateCheckInDashboard
+
+	// Attendance report states
+	StateAttendanceReport
+
+	// Participant removal and ban list states
+	StateRemovingParticipant
+	StateSelectRemoveParticipantMatch
+	StateConfirmRemoveParticipant
+	StateBanningUser
+	StateUnbanningUser
+
+	// Approval-required event states
+	StateSettingApproval
+	StateListApplications
+	StateApprovingApplication
+	StateRejectingApplication
+
+	// Invite link states
+	StateCreatingInvite
+	StateEnteringInviteLabel
+	StateEnteringInviteMaxUses
+	StateEnteringInviteExpiry
+	StateListInvites
+	StateRevokingInvite
+	StateSettingInviteOnly
+
+	// Coowner role states
+	StateSettingCoownerRole
+
+	// Coowner invite states
+	StateInvitingCoowner
+	StateListingCoowners
+
+	// Ownership transfer states
+	StateTransferringOwnership
+	StateAcceptingOwnership
+	StateDecliningOwnership
+
+	// Event lifecycle states
+	StatePublishingEvent
+	StateCancellingEvent
+	StateConfirmCancelEvent
+	StateCompletingEvent
+
+	// Change notice states
+	StateConfirmChangeNotice
+
+	// Event deletion states
+	StateConfirmDeleteEvent
+	StateRestoringEvent
+
+	// Cloning and template states
+	StateCloningEvent
+	StateSelectEventTemplate
+	StateEnteringCopyName
+	StateEnteringCopyDate
+	StateSavingTemplate
+	StateDeletingTemplate
+
+	// Recurring series states
+	StateCreatingSeries
+	StateSelectSeriesEditScope
+	StateViewingSeriesAttendance
+	StateJoiningSeries // Participant bot: whether to join the rest of a series
+
+	// Agenda states
+	StateAddingAgendaSession
+	StateViewingAgenda
+	StateRemovingAgendaSession
+	StateViewingSessionRoster
+	StateCheckingInSession
+	StateUndoingSessionCheckIn
+	StateSelectAgendaEvent // Participant bot: which event's agenda to browse
+	StateBrowsingAgenda    // Participant bot: picking sessions
+
+	// Guest states
+	StateSettingGuestPolicy
+	StateSettingCapacity
+	StateCheckingInGuests
+	StateUndoingGuestCheckIn
+	StateSelectGuestsEvent  // Participant bot: which event's guests to change
+	StateEnteringGuestCount // Participant bot: how many guests they bring
+	StateEnteringGuestName  // Participant bot: each guest's name
+	StateAnsweringGuestRSVP // Participant bot: RSVP questions for each guest
+
 	//Participant Bot
 	StateCheckIn
 	StatePersonalNotes
@@ -105,4 +318,6 @@ const (
 	StateAnsweringRSVPQuestion
 	StateSelectEventForRSVP
 	StateEnteringCheckInCode // New state for entering check-in code
+	StateRequestingTicket
+	StateSharingCheckInLocation
 )
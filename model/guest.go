@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Guest is someone a participant registers along with themselves, such as a partner who doesn't use Telegram
+type Guest struct {
+	Name        string       `firestore:"name"`
+	RSVPAnswers []RSVPAnswer `firestore:"rsvpAnswers"` // Only collected when the event asks guests its RSVP questions
+	CheckedIn   bool         `firestore:"checkedIn"`
+	CheckedInAt time.Time    `firestore:"checkedInAt"`
+	CheckedInBy int64        `firestore:"checkedInBy"`
+}
+
+// GuestsCheckedIn counts the participant's guests who have arrived
+func (s *SignedUpEvent) GuestsCheckedIn() int {
+	count := 0
+	for _, guest := range s.Guests {
+		if guest.CheckedIn {
+			count++
+		}
+	}
+	return count
+}
+
+// Headcount is how many people are registered for the event, guests included; applicants count once approved
+func (e *Event) Headcount() int {
+	count := len(e.Participants)
+	for _, participantID := range e.Participants {
+		count += e.GuestCounts[participantID]
+	}
+	return count
+}
+
+// PlacesLeft is how many more people can register, or -1 if the event has no capacity
+func (e *Event) PlacesLeft() int {
+	if e.Capacity == 0 {
+		return -1
+	}
+	return max(e.Capacity-e.Headcount(), 0)
+}
+
+// IsFull reports whether the event has reached its capacity
+func (e *Event) IsFull() bool {
+	return e.PlacesLeft() == 0
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Invite is a join link for an event that can be limited in uses and lifetime, and revoked
+type Invite struct {
+	Token     string    `firestore:"token"` // Also the document ID and the deep link parameter
+	EventID   string    `firestore:"eventID"`
+	Label     string    `firestore:"label"`   // e.g. "VIP" or "Partner org"
+	MaxUses   int       `firestore:"maxUses"` // 0 for unlimited
+	Uses      int       `firestore:"uses"`
+	ExpiresAt time.Time `firestore:"expiresAt"` // Zero for never
+	Revoked   bool      `firestore:"revoked"`
+	CreatedBy int64     `firestore:"createdBy"`
+	CreatedAt time.Time `firestore:"createdAt"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "slices"
+
+// EventStatus tracks where an event is in its lifecycle
+type EventStatus string
+
+const (
+	EventStatusDraft     EventStatus = "draft"     // Being prepared, participants can't join yet
+	EventStatusPublished EventStatus = "published" // Open for joining and check-in
+	EventStatusCancelled EventStatus = "cancelled" // Called off, participants have been told
+	EventStatusCompleted EventStatus = "completed" // Over and read-only
+)
+
+// eventStatusChanges lists the statuses each status can move to
+var eventStatusChanges = map[EventStatus][]EventStatus{
+	EventStatusDraft:     {EventStatusPublished, EventStatusCancelled},
+	EventStatusPublished: {EventStatusCancelled, EventStatusCompleted},
+}
+
+// CanBecome reports whether an event with this status may be moved to next
+func (s EventStatus) CanBecome(next EventStatus) bool {
+	return slices.Contains(eventStatusChanges[s], next)
+}
+
+// CurrentStatus returns the event's status; events created before statuses existed are published
+func (e *Event) CurrentStatus() EventStatus {
+	if e.Status == "" {
+		return EventStatusPublished
+	}
+	return e.Status
+}
+
+// IsDeleted reports whether the event has been deleted and is waiting to be purged
+func (e *Event) IsDeleted() bool {
+	return !e.DeletedAt.IsZero()
+}
+
+// Allows reports whether the user's role grants the permission in the event's current status.
+// Completed events are read-only, so only viewing, team changes and deletion remain.
+// Deleted events can only be restored by those allowed to delete them.
+func (e *Event) Allows(userID int64, permission Permission) bool {
+	if !e.RoleOf(userID).Can(permission) {
+		return false
+	}
+	if e.IsDeleted() {
+		return permission == PermissionDeleteEvent
+	}
+	if e.CurrentStatus() == EventStatusCompleted {
+		switch permission {
+		case PermissionEditEvent, PermissionManageParticipants, PermissionMessage:
+			return false
+		}
+	}
+	return true
+}
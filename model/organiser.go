@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Organiser holds per-organiser preferences, keyed by Telegram user ID
+type Organiser struct {
+	UserID      int64        `firestore:"userid"`
+	Name        string       `firestore:"name"`        // Telegram first name, recorded when they join an event's team
+	Username    string       `firestore:"username"`    // Telegram @username, may be empty
+	Timezone    string       `firestore:"timezone"`    // IANA zone name, e.g. "Asia/Singapore"
+	BannedUsers []BannedUser `firestore:"bannedUsers"` // Users who may not join this organiser's events
+}
+
+type BannedUser struct {
+	UserID   int64     `firestore:"userid"`
+	Name     string    `firestore:"name"`
+	Reason   string    `firestore:"reason"`
+	BannedAt time.Time `firestore:"bannedAt"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+)
+
+// CoownerRole is what a collaborator may do on an event
+type CoownerRole string
+
+const (
+	RoleOwner     CoownerRole = "owner" // The primary owner, never stored as a coowner role
+	RoleAdmin     CoownerRole = "admin"
+	RoleEditor    CoownerRole = "editor"
+	RoleMessenger CoownerRole = "messenger"
+	RoleDoorStaff CoownerRole = "door"
+	RoleViewer    CoownerRole = "viewer"
+)
+
+// CoownerRoles lists the roles that can be given to coowners, most powerful first
+var CoownerRoles = []CoownerRole{RoleAdmin, RoleEditor, RoleMessenger, RoleDoorStaff, RoleViewer}
+
+// Permission is an action on an event that is limited to some roles
+type Permission int
+
+const (
+	PermissionDeleteEvent        Permission = iota // Delete the event
+	PermissionManageTeam                           // Add and remove coowners and checkers, change roles
+	PermissionEditEvent                            // Edit details, venue, check-in windows, joining rules and invites
+	PermissionManageParticipants                   // Approve, reject and remove participants
+	PermissionMessage                              // Send and schedule blasts
+	PermissionCheckIn                              // Check-in codes, ticket scanning, manual check-in and the dashboard
+	PermissionViewParticipants                     // Participant lists, exports and reports
+)
+
+var rolePermissions = map[CoownerRole][]Permission{
+	RoleOwner: {PermissionDeleteEvent, PermissionManageTeam, PermissionEditEvent, PermissionManageParticipants,
+		PermissionMessage, PermissionCheckIn, PermissionViewParticipants},
+	RoleAdmin: {PermissionManageTeam, PermissionEditEvent, PermissionManageParticipants, PermissionMessage,
+		PermissionCheckIn, PermissionViewParticipants},
+	RoleEditor:    {PermissionEditEvent, PermissionManageParticipants, PermissionViewParticipants},
+	RoleMessenger: {PermissionMessage, PermissionViewParticipants},
+	RoleDoorStaff: {PermissionCheckIn},
+	RoleViewer:    {PermissionViewParticipants},
+}
+
+// Can reports whether the role grants the permission
+func (r CoownerRole) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// ParseCoownerRole parses the name of a role that can be given to a coowner
+func ParseCoownerRole(name string) (CoownerRole, bool) {
+	role := CoownerRole(name)
+	return role, slices.Contains(CoownerRoles, role)
+}
+
+// RoleOf returns the user's role on the event, or "" if they are neither the owner nor a coowner
+func (e *Event) RoleOf(userID int64) CoownerRole {
+	if e.UserID == userID {
+		return RoleOwner
+	}
+	if !slices.Contains(e.Coowners, userID) {
+		return ""
+	}
+	if role, ok := e.CoownerRoles[strconv.FormatInt(userID, 10)]; ok {
+		return role
+	}
+	// Coowners added before roles existed keep full access short of deleting the event
+	return RoleAdmin
+}
+
+// MembersWith returns the owner and the coowners whose role grants the permission
+func (e *Event) MembersWith(permission Permission) []int64 {
+	members := []int64{e.UserID}
+	for _, coownerID := range e.Coowners {
+		if e.RoleOf(coownerID).Can(permission) {
+			members = append(members, coownerID)
+		}
+	}
+	return members
+}
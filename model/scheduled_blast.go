@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// BlastStatus tracks where a scheduled blast is in its lifecycle
+type BlastStatus string
+
+const (
+	BlastStatusPending   BlastStatus = "pending"
+	BlastStatusSending   BlastStatus = "sending"
+	BlastStatusSent      BlastStatus = "sent"
+	BlastStatusCancelled BlastStatus = "cancelled"
+	BlastStatusFailed    BlastStatus = "failed"
+)
+
+type ScheduledBlast struct {
+	ID           string      `firestore:"id"`
+	EventID      string      `firestore:"eventID"`
+	CreatedBy    int64       `firestore:"createdBy"` // Organiser who scheduled the blast
+	Message      string      `firestore:"message"`
+	SendAt       time.Time   `firestore:"sendAt"` // Stored in UTC
+	Status       BlastStatus `firestore:"status"`
+	SentAt       time.Time   `firestore:"sentAt"`
+	FailedAt     time.Time   `firestore:"failedAt"` // When sending failed or was found interrupted
+	SuccessCount int         `firestore:"successCount"`
+	FailureCount int         `firestore:"failureCount"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// EventSeries links the occurrences of a recurring event, such as a weekly session
+type EventSeries struct {
+	ID        string       `firestore:"id"`
+	Name      string       `firestore:"name"`
+	OwnerID   int64        `firestore:"ownerID"`
+	Rule      string       `firestore:"rule"` // As the organiser wrote it, e.g. "every Tuesday 19:00 for 10 weeks"
+	Weekday   time.Weekday `firestore:"weekday"`
+	StartTime string       `firestore:"startTime"` // "15:04" in the organiser's timezone
+	EventIDs  []string     `firestore:"eventIDs"`  // Occurrences in date order
+	CreatedAt time.Time    `firestore:"createdAt"`
+}
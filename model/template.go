@@ -0,0 +1,75 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// EventTemplate is a named event blueprint an organiser can start new events from
+type EventTemplate struct {
+	ID        string    `firestore:"id"`
+	Name      string    `firestore:"name"`
+	OwnerID   int64     `firestore:"ownerID"`
+	Event     Event     `firestore:"event"` // Blueprint without a name, date, participants or status
+	CreatedAt time.Time `firestore:"createdAt"`
+}
+
+// Blueprint copies what a new event can reuse from this one: details, RSVP questions, agenda, images, venue, team, guest policy and joining rules.
+// Its name, date, participants, check-in codes and windows, status and deletion are left for the new event to set.
+func (e *Event) Blueprint() Event {
+	blueprint := Event{
+		UserID:           e.UserID,
+		Coowners:         slices.Clone(e.Coowners),
+		CoownerRoles:     maps.Clone(e.CoownerRoles),
+		EDMFileID:        e.EDMFileID,
+		EDMFileURL:       e.EDMFileURL,
+		EventDetails:     slices.Clone(e.EventDetails),
+		RSVPQuestions:    slices.Clone(e.RSVPQuestions),
+		Checkers:         slices.Clone(e.Checkers),
+		CheckInRadius:    e.CheckInRadius,
+		RequiresApproval: e.RequiresApproval,
+		InviteOnly:       e.InviteOnly,
+		MaxGuests:        e.MaxGuests,
+		GuestRSVP:        e.GuestRSVP,
+		Capacity:         e.Capacity,
+	}
+	for i := range blueprint.RSVPQuestions {
+		blueprint.RSVPQuestions[i].Options = slices.Clone(blueprint.RSVPQuestions[i].Options)
+	}
+	for _, session := range e.Agenda {
+		session.Attendees = nil
+		session.CheckedIn = nil
+		blueprint.Agenda = append(blueprint.Agenda, session)
+	}
+	if e.Venue != nil {
+		venue := *e.Venue
+		if venue.Coordinates != nil {
+			coordinates := *venue.Coordinates
+			venue.Coordinates = &coordinates
+		}
+		blueprint.Venue = &venue
+	}
+	return blueprint
+}
+
+// HandOver makes userID the owner of a blueprint, keeping the previous owner on the team as an admin
+func (e *Event) HandOver(userID int64) {
+	if e.UserID == userID {
+		return
+	}
+	previousOwnerID := e.UserID
+	e.UserID = userID
+	if i := slices.Index(e.Coowners, userID); i >= 0 {
+		e.Coowners = slices.Delete(e.Coowners, i, i+1)
+		delete(e.CoownerRoles, strconv.FormatInt(userID, 10))
+	}
+	if previousOwnerID != 0 {
+		e.Coowners = append(e.Coowners, previousOwnerID)
+		if e.CoownerRoles == nil {
+			e.CoownerRoles = make(map[string]CoownerRole)
+		}
+		e.CoownerRoles[strconv.FormatInt(previousOwnerID, 10)] = RoleAdmin
+	}
+}
@@ -3,8 +3,19 @@ package model
 type UserState struct {
 	State               int
 	CurrentEvent        *Event
-	LastQuestion        string        // Store the last question asked
-	CurrentRSVPQuestion *RSVPQuestion // Current RSVP question being created
-	RSVPQuestionIndex   int           // Index of current RSVP question being answered
-	TempOptions         []string      // Temporary storage for MCQ or MultiSelect options
+	LastQuestion        string          // Store the last question asked
+	CurrentRSVPQuestion *RSVPQuestion   // Current RSVP question being created
+	RSVPQuestionIndex   int             // Index of current RSVP question being answered
+	TempOptions         []string        // Temporary storage for MCQ or MultiSelect options
+	CurrentBlast        *ScheduledBlast // Blast being composed, scheduled or edited
+
+	// Change notice waiting for the organiser to confirm
+	PendingChange        string   // Description of the change participants will receive
+	ChangedOccurrenceIDs []string // Later sessions of a series the change was copied to
+
+	// Guest registration in the participant bot
+	GuestAllowance int      // Most guests the participant may register
+	GuestCount     int      // Number of guests being named
+	GuestNames     []string // Names of the guests entered so far
+	GuestIndex     int      // Guest whose RSVP questions are being asked
 }
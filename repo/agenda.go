@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// AddAgendaSession adds a session to the event's agenda and returns the updated event
+func (fc *FirestoreConnector) AddAgendaSession(ctx context.Context, eventID string, session model.AgendaSession) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		session.ID = uuid.NewString()
+		event.Agenda = append(event.Agenda, session)
+		event.SortAgenda()
+		return nil
+	})
+}
+
+// RemoveAgendaSession drops a session from the event's agenda and returns it as it was
+func (fc *FirestoreConnector) RemoveAgendaSession(ctx context.Context, eventID string, sessionID string) (*model.AgendaSession, error) {
+	var removed model.AgendaSession
+	_, err := fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		i := slices.IndexFunc(event.Agenda, func(s model.AgendaSession) bool { return s.ID == sessionID })
+		if i < 0 {
+			return model.ErrSessionDoesNotExist
+		}
+		removed = event.Agenda[i]
+		event.Agenda = slices.Delete(event.Agenda, i, i+1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &removed, nil
+}
+
+// JoinAgendaSession signs a participant of the event up for one of its sessions if it has room and doesn't clash with their other sessions
+func (fc *FirestoreConnector) JoinAgendaSession(ctx context.Context, eventID string, sessionID string, participantID string) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if !slices.Contains(event.Participants, participantID) {
+			return model.ErrNotSignedUp
+		}
+		session := event.FindAgendaSession(sessionID)
+		if session == nil {
+			return model.ErrSessionDoesNotExist
+		}
+		if slices.Contains(session.Attendees, participantID) {
+			return nil
+		}
+		if session.IsFull() {
+			return model.ErrSessionFull
+		}
+		if event.ClashingSession(session, participantID) != nil {
+			return model.ErrSessionClash
+		}
+		session.Attendees = append(session.Attendees, participantID)
+		return nil
+	})
+}
+
+// LeaveAgendaSession takes a participant off one of the event's sessions
+func (fc *FirestoreConnector) LeaveAgendaSession(ctx context.Context, eventID string, sessionID string, participantID string) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		session := event.FindAgendaSession(sessionID)
+		if session == nil {
+			return model.ErrSessionDoesNotExist
+		}
+		i := slices.Index(session.Attendees, participantID)
+		if i < 0 {
+			return model.ErrNotInSession
+		}
+		session.Attendees = slices.Delete(session.Attendees, i, i+1)
+		session.CheckedIn = slices.DeleteFunc(session.CheckedIn, func(id string) bool { return id == participantID })
+		return nil
+	})
+}
+
+// SetAgendaCheckIn checks a participant in at a session, or undoes it.
+// Participants who turn up to a session they didn't pick are added to it as walk-ins.
+func (fc *FirestoreConnector) SetAgendaCheckIn(ctx context.Context, eventID string, sessionID string, participantID string, checkedIn bool) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if !slices.Contains(event.Participants, participantID) {
+			return model.ErrNotSignedUp
+		}
+		session := event.FindAgendaSession(sessionID)
+		if session == nil {
+			return model.ErrSessionDoesNotExist
+		}
+
+		i := slices.Index(session.CheckedIn, participantID)
+		if !checkedIn {
+			if i < 0 {
+				return model.ErrNotCheckedIn
+			}
+			session.CheckedIn = slices.Delete(session.CheckedIn, i, i+1)
+			return nil
+		}
+
+		if i >= 0 {
+			return model.ErrAlreadyCheckedIn
+		}
+		if !slices.Contains(session.Attendees, participantID) {
+			session.Attendees = append(session.Attendees, participantID)
+		}
+		session.CheckedIn = append(session.CheckedIn, participantID)
+		return nil
+	})
+}
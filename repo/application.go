@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"log"
+	"slices"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ListApplicants lists the participants whose applications to an event are awaiting approval
+func (fc *FirestoreConnector) ListApplicants(ctx context.Context, eventID string) ([]model.Participant, error) {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return nil, model.ErrEventDoesNotExist
+	}
+
+	var applicants []model.Participant
+	for _, participantID := range event.Applicants {
+		participant, err := fc.ReadParticipantByID(ctx, participantID)
+		if err != nil {
+			log.Printf("error reading applicant with ID '%s': %v", participantID, err)
+			continue
+		}
+		applicants = append(applicants, *participant)
+	}
+	return applicants, nil
+}
+
+// ApproveApplication atomically moves an applicant into the event's confirmed participants,
+// returning model.ErrEventFull if they and their guests don't fit within the event's capacity
+func (fc *FirestoreConnector) ApproveApplication(ctx context.Context, eventID string, participantID string) (*model.Participant, error) {
+	return fc.decideApplication(ctx, eventID, participantID, true)
+}
+
+// RejectApplication atomically drops an application, removing the applicant's sign-up for the event
+func (fc *FirestoreConnector) RejectApplication(ctx context.Context, eventID string, participantID string) (*model.Participant, error) {
+	return fc.decideApplication(ctx, eventID, participantID, false)
+}
+
+func (fc *FirestoreConnector) decideApplication(ctx context.Context, eventID string, participantID string, approve bool) (*model.Participant, error) {
+	eventRef := fc.client.Collection("events").Doc(eventID)
+	participantRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return err
+		}
+		var event model.Event
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
+		}
+
+		participantDoc, err := tx.Get(participantRef)
+		if err != nil {
+			return err
+		}
+		if err := participantDoc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		i := slices.Index(event.Applicants, participantID)
+		j := slices.IndexFunc(participant.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID && s.PendingApproval
+		})
+		if i < 0 || j < 0 {
+			return model.ErrNoApplication
+		}
+
+		event.Applicants = slices.Delete(event.Applicants, i, i+1)
+		if approve {
+			if event.Capacity > 0 && event.Headcount()+1+event.GuestCounts[participantID] > event.Capacity {
+				return model.ErrEventFull
+			}
+			participant.SignedUpEvents[j].PendingApproval = false
+			if !slices.Contains(event.Participants, participantID) {
+				event.Participants = append(event.Participants, participantID)
+			}
+		} else {
+			participant.SignedUpEvents = slices.Delete(participant.SignedUpEvents, j, j+1)
+			delete(event.GuestCounts, participantID)
+		}
+
+		if err := tx.Set(eventRef, event); err != nil {
+			return err
+		}
+		return tx.Set(participantRef, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+)
+
+// CreateAuditEntry appends an entry to the audit log
+func (fc *FirestoreConnector) CreateAuditEntry(ctx context.Context, entry model.AuditEntry) error {
+	docRef := fc.client.Collection("auditLog").NewDoc()
+	entry.ID = docRef.ID
+	_, err := docRef.Set(ctx, entry)
+	return err
+}
@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// CheckInParticipant atomically marks a participant as checked in to an event by the given user, rejecting duplicate check-ins
+func (fc *FirestoreConnector) CheckInParticipant(ctx context.Context, participantID string, eventID string, at time.Time, by int64) (*model.Participant, error) {
+	docRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		for i := range participant.SignedUpEvents {
+			if participant.SignedUpEvents[i].EventID != eventID {
+				continue
+			}
+			if participant.SignedUpEvents[i].PendingApproval {
+				return model.ErrApplicationPending
+			}
+			if participant.SignedUpEvents[i].CheckedIn {
+				return model.ErrAlreadyCheckedIn
+			}
+			participant.SignedUpEvents[i].CheckedIn = true
+			participant.SignedUpEvents[i].CheckedInAt = at.UTC()
+			participant.SignedUpEvents[i].CheckedInBy = by
+			return tx.Set(docRef, participant)
+		}
+		return model.ErrNotSignedUp
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// UndoCheckIn atomically clears a participant's check-in for an event
+func (fc *FirestoreConnector) UndoCheckIn(ctx context.Context, participantID string, eventID string) (*model.Participant, error) {
+	docRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		for i := range participant.SignedUpEvents {
+			if participant.SignedUpEvents[i].EventID != eventID {
+				continue
+			}
+			if !participant.SignedUpEvents[i].CheckedIn {
+				return model.ErrNotCheckedIn
+			}
+			participant.SignedUpEvents[i].CheckedIn = false
+			participant.SignedUpEvents[i].CheckedInAt = time.Time{}
+			participant.SignedUpEvents[i].CheckedInBy = 0
+			return tx.Set(docRef, participant)
+		}
+		return model.ErrNotSignedUp
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
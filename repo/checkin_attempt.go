@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func checkInAttemptDocID(eventID string, userID int64) string {
+	return fmt.Sprintf("%s_%d", eventID, userID)
+}
+
+// ReadCheckInAttempt reads a user's failed attempts for an event, returning an empty record if there are none
+func (fc *FirestoreConnector) ReadCheckInAttempt(ctx context.Context, eventID string, userID int64) (*model.CheckInAttempt, error) {
+	doc, err := fc.client.Collection("checkInAttempts").Doc(checkInAttemptDocID(eventID, userID)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return &model.CheckInAttempt{EventID: eventID, UserID: userID}, nil
+		}
+		return nil, err
+	}
+
+	var attempt model.CheckInAttempt
+	err = doc.DataTo(&attempt)
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// UpdateCheckInAttempt creates or overwrites a user's failed attempts for an event
+func (fc *FirestoreConnector) UpdateCheckInAttempt(ctx context.Context, attempt model.CheckInAttempt) error {
+	_, err := fc.client.Collection("checkInAttempts").Doc(checkInAttemptDocID(attempt.EventID, attempt.UserID)).Set(ctx, attempt)
+	return err
+}
+
+// RecordEventCheckInFailure atomically counts a failed attempt against the event, starting a new window once the old one has passed
+func (fc *FirestoreConnector) RecordEventCheckInFailure(ctx context.Context, eventID string, window time.Duration, now time.Time) (*model.EventCheckInGuard, error) {
+	docRef := fc.client.Collection("checkInGuards").Doc(eventID)
+
+	var guard model.EventCheckInGuard
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		guard = model.EventCheckInGuard{EventID: eventID}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&guard); err != nil {
+				return err
+			}
+		}
+
+		if now.Sub(guard.WindowStart) > window {
+			guard.WindowStart = now.UTC()
+			guard.WindowFailures = 0
+		}
+		guard.WindowFailures++
+		return tx.Set(docRef, guard)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &guard, nil
+}
+
+// UpdateEventCheckInGuard overwrites an event's failed attempt counter
+func (fc *FirestoreConnector) UpdateEventCheckInGuard(ctx context.Context, guard model.EventCheckInGuard) error {
+	_, err := fc.client.Collection("checkInGuards").Doc(guard.EventID).Set(ctx, guard)
+	return err
+}
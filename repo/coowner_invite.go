@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateCoownerInvite stores a new coowner invite under a random token and returns the token
+func (fc *FirestoreConnector) CreateCoownerInvite(ctx context.Context, invite model.CoownerInvite) (string, error) {
+	docRef := fc.client.Collection("coownerInvites").NewDoc()
+	invite.Token = docRef.ID
+	_, err := docRef.Set(ctx, invite)
+	if err != nil {
+		return "", err
+	}
+	return docRef.ID, nil
+}
+
+// AcceptCoownerInvite atomically uses up a coowner invite and adds the user to the event's coowners with the invite's role
+func (fc *FirestoreConnector) AcceptCoownerInvite(ctx context.Context, token string, userID int64, now time.Time) (*model.CoownerInvite, error) {
+	inviteRef := fc.client.Collection("coownerInvites").Doc(token)
+
+	var invite model.CoownerInvite
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		inviteDoc, err := tx.Get(inviteRef)
+		if status.Code(err) == codes.NotFound {
+			return model.ErrInviteDoesNotExist
+		} else if err != nil {
+			return err
+		}
+		if err := inviteDoc.DataTo(&invite); err != nil {
+			return err
+		}
+
+		if invite.UsedBy != 0 {
+			return model.ErrInviteUsedUp
+		}
+		if !now.Before(invite.ExpiresAt) {
+			return model.ErrInviteExpired
+		}
+
+		eventRef := fc.client.Collection("events").Doc(invite.EventID)
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return fmt.Errorf("error reading event: %w", err)
+		}
+		var event model.Event
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
+		}
+
+		if event.UserID == userID || slices.Contains(event.Coowners, userID) {
+			return model.ErrAlreadyCoowner
+		}
+		event.Coowners = append(event.Coowners, userID)
+		if event.CoownerRoles == nil {
+			event.CoownerRoles = make(map[string]model.CoownerRole)
+		}
+		event.CoownerRoles[strconv.FormatInt(userID, 10)] = invite.Role
+
+		invite.UsedBy = userID
+		invite.UsedAt = now.UTC()
+		if err := tx.Set(eventRef, event); err != nil {
+			return err
+		}
+		return tx.Set(inviteRef, invite)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SoftDeleteEvent hides the event until purgeAt, before which it can still be restored
+func (fc *FirestoreConnector) SoftDeleteEvent(ctx context.Context, eventID string, deletedBy int64, at time.Time, purgeAt time.Time, notify bool) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if event.IsDeleted() {
+			return model.ErrEventDeleted
+		}
+		event.DeletedAt = at.UTC()
+		event.DeletedBy = deletedBy
+		event.PurgeAt = purgeAt.UTC()
+		event.NotifyOnPurge = notify
+		return nil
+	})
+}
+
+// RestoreEvent undoes a soft delete while the restore window is still open
+func (fc *FirestoreConnector) RestoreEvent(ctx context.Context, eventID string, now time.Time) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if !event.IsDeleted() {
+			return model.ErrEventNotDeleted
+		}
+		if !now.Before(event.PurgeAt) {
+			return model.ErrRestoreWindowClosed
+		}
+		event.DeletedAt = time.Time{}
+		event.DeletedBy = 0
+		event.PurgeAt = time.Time{}
+		event.NotifyOnPurge = false
+		return nil
+	})
+}
+
+// ListEventsDueForPurge returns deleted events whose restore window has closed by now
+func (fc *FirestoreConnector) ListEventsDueForPurge(ctx context.Context, now time.Time) ([]model.Event, error) {
+	// Events that were never deleted store a zero purgeAt, which sorts before the Unix epoch
+	iter := fc.client.Collection("events").
+		Where("purgeAt", ">", time.Unix(0, 0)).
+		Where("purgeAt", "<=", now.UTC()).
+		Documents(ctx)
+
+	var events []model.Event
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var event model.Event
+		if err := doc.DataTo(&event); err != nil {
+			log.Printf("error converting document data to event: %v", err)
+			continue
+		}
+		event.ID = doc.Ref.ID
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// PurgeEvent permanently removes a deleted event whose restore window has closed.
+// It takes the event off every participant's sign-ups, cancels its pending blasts and returns the participants it was removed from.
+func (fc *FirestoreConnector) PurgeEvent(ctx context.Context, eventID string, now time.Time) ([]model.Participant, error) {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsDeleted() || now.Before(event.PurgeAt) {
+		return nil, model.ErrEventNotDeleted
+	}
+
+	var removed []model.Participant
+	for _, participantID := range append(slices.Clone(event.Participants), event.Applicants...) {
+		participant, err := fc.removeSignUp(ctx, participantID, eventID)
+		if status.Code(err) == codes.NotFound || errors.Is(err, model.ErrNotSignedUp) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error removing event from participant %s: %w", participantID, err)
+		}
+		removed = append(removed, *participant)
+	}
+
+	blasts, err := fc.ListScheduledBlastsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scheduled blasts: %w", err)
+	}
+	for _, blast := range blasts {
+		if blast.Status != model.BlastStatusPending {
+			continue
+		}
+		blast.Status = model.BlastStatusCancelled
+		if err := fc.UpdatePendingScheduledBlast(ctx, blast); err != nil && !errors.Is(err, model.ErrBlastNotPending) {
+			return nil, fmt.Errorf("error cancelling scheduled blast %s: %w", blast.ID, err)
+		}
+	}
+
+	return removed, fc.DeleteEvent(ctx, eventID)
+}
+
+// removeSignUp atomically drops the event from a participant's sign-ups
+func (fc *FirestoreConnector) removeSignUp(ctx context.Context, participantID string, eventID string) (*model.Participant, error) {
+	docRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(participant.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID
+		})
+		if i < 0 {
+			return model.ErrNotSignedUp
+		}
+		participant.SignedUpEvents = slices.Delete(participant.SignedUpEvents, i, i+1)
+		return tx.Set(docRef, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"strconv"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
+	"github.com/google/uuid"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // FirestoreConnector struct to hold Firestore client
@@ -153,71 +158,86 @@ func (fc *FirestoreConnector) ListEventsByUserID(ctx context.Context, userID int
 	return events, nil
 }
 
-// CreateParticipant adds a new participant to the participants collection and then adds the participant's ID to the event's participant list
-func (fc *FirestoreConnector) CreateParticipant(ctx context.Context, eventID string, participant *model.Participant) error {
-	// Check if the event exists
-	event, err := fc.ReadEvent(ctx, eventID)
-	if err != nil {
-		return model.ErrEventDoesNotExist
-	}
-
-	// Check if the participant already exists for the event
-	existingParticipant, err := fc.ReadParticipantByUserID(ctx, participant.UserID)
-	if err == nil && existingParticipant != nil {
-		log.Printf("Participant with userID '%d' already exists", participant.UserID)
-
-		// Check if the participant is already signed up for the event
-		exist := false
-		for i := range existingParticipant.SignedUpEvents {
-			if existingParticipant.SignedUpEvents[i].EventID == eventID {
-				exist = true
-				break
-			}
+// CreateParticipant signs the user up for the event, creating their participant document on their first sign-up.
+// The sign-up is rejected with model.ErrEventFull if the event has reached its capacity.
+// Reports whether a new sign-up was created, which is false if the user had already signed up.
+func (fc *FirestoreConnector) CreateParticipant(ctx context.Context, eventID string, participant *model.Participant) (bool, error) {
+	eventRef := fc.client.Collection("events").Doc(eventID)
+	query := fc.client.Collection("participants").Where("userid", "==", participant.UserID).Limit(1)
+
+	created := false
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		created = false
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return model.ErrEventDoesNotExist
+		}
+		var event model.Event
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
 		}
 
-		if !exist {
-			existingParticipant.SignedUpEvents = append(existingParticipant.SignedUpEvents, model.SignedUpEvent{
-				EventID:       eventID,
-				PersonalNotes: "",
-				CheckedIn:     false,
-			})
-			err = fc.UpdateParticipant(ctx, *existingParticipant)
-			if err != nil {
+		// Reuse the participant's document if they have signed up for anything before
+		signUp := *participant
+		iter := tx.Documents(query)
+		defer iter.Stop()
+		participantDoc, err := iter.Next()
+		var participantRef *firestore.DocumentRef
+		switch {
+		case err == iterator.Done:
+			participantRef = fc.client.Collection("participants").NewDoc()
+			signUp.ID = participantRef.ID
+		case err != nil:
+			return err
+		default:
+			participantRef = participantDoc.Ref
+			if err := participantDoc.DataTo(&signUp); err != nil {
 				return err
 			}
+			// Keep the Telegram username current, it can change between sign-ups
+			signUp.Username = participant.Username
 		}
 
-		participant = existingParticipant
-	} else if err != nil {
-		return err
-	} else {
-		participant.SignedUpEvents = append(participant.SignedUpEvents, model.SignedUpEvent{
-			EventID:       eventID,
-			PersonalNotes: "",
-			CheckedIn:     false,
+		exist := slices.ContainsFunc(signUp.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID
 		})
-		docRef, _, err := fc.client.Collection("participants").Add(ctx, participant)
-		if err != nil {
-			return err
+		if !exist {
+			// Full events only take people who are already registered
+			if event.IsFull() {
+				return model.ErrEventFull
+			}
+			signUp.SignedUpEvents = append(signUp.SignedUpEvents, model.SignedUpEvent{
+				EventID:         eventID,
+				PersonalNotes:   "",
+				CheckedIn:       false,
+				JoinedAt:        time.Now().UTC(),
+				TicketID:        uuid.NewString(),
+				PendingApproval: event.RequiresApproval,
+			})
+			created = true
 		}
 
-		participant.ID = docRef.ID
-		err = fc.UpdateParticipant(ctx, *participant)
-		if err != nil {
-			return err
+		// Applicants awaiting approval are kept apart from confirmed participants
+		pending := slices.ContainsFunc(signUp.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID && s.PendingApproval
+		})
+		if pending {
+			if !slices.Contains(event.Applicants, signUp.ID) {
+				event.Applicants = append(event.Applicants, signUp.ID)
+			}
+		} else if !slices.Contains(event.Participants, signUp.ID) {
+			event.Participants = append(event.Participants, signUp.ID)
 		}
-	}
 
-	if !slices.Contains(event.Participants, participant.ID) {
-		// Add the participant's ID to the event's participants list
-		event.Participants = append(event.Participants, participant.ID)
-		err = fc.UpdateEvent(ctx, eventID, *event)
-		if err != nil {
+		if err := tx.Set(eventRef, event); err != nil {
 			return err
 		}
+		return tx.Set(participantRef, signUp)
+	})
+	if err != nil {
+		return false, err
 	}
-
-	return nil
+	return created, nil
 }
 
 // ReadParticipant reads a participant from an event in Firestore by their code
@@ -311,7 +331,10 @@ func (fc *FirestoreConnector) ListEventsByParticipantUserID(ctx context.Context,
 
 	for i := range participant.SignedUpEvents {
 		event, err := fc.ReadEvent(ctx, participant.SignedUpEvents[i].EventID)
-		if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// The event was removed before its sign-ups were cleaned up
+			continue
+		} else if err != nil {
 			return participantEvents, err
 		}
 
@@ -337,17 +360,25 @@ func (fc *FirestoreConnector) IsEventOwner(ctx context.Context, eventID string,
 	return event.UserID == userID || slices.Contains(event.Coowners, userID), nil
 }
 
-// AddCoowner adds a coowner to an event
-func (fc *FirestoreConnector) AddCoowner(ctx context.Context, eventID string, primaryOwnerID, coownerID int64) error {
-	// First, verify that the user trying to add a coowner is the primary owner
+// HasEventPermission checks if the user's role on the event grants the permission
+func (fc *FirestoreConnector) HasEventPermission(ctx context.Context, eventID string, userID int64, permission model.Permission) (bool, error) {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+
+	return event.Allows(userID, permission), nil
+}
+
+// AddCoowner adds a coowner with the given role to an event
+func (fc *FirestoreConnector) AddCoowner(ctx context.Context, eventID string, coownerID int64, role model.CoownerRole) error {
 	event, err := fc.ReadEvent(ctx, eventID)
 	if err != nil {
 		return err
 	}
 
-	// Check if the primary owner is actually the owner
-	if event.UserID != primaryOwnerID {
-		return fmt.Errorf("only the primary owner can add coowners")
+	if event.UserID == coownerID {
+		return fmt.Errorf("user is the event owner")
 	}
 
 	// Check if the coowner is already in the list
@@ -357,22 +388,38 @@ func (fc *FirestoreConnector) AddCoowner(ctx context.Context, eventID string, pr
 
 	// Add the coowner
 	event.Coowners = append(event.Coowners, coownerID)
+	if event.CoownerRoles == nil {
+		event.CoownerRoles = make(map[string]model.CoownerRole)
+	}
+	event.CoownerRoles[strconv.FormatInt(coownerID, 10)] = role
 
 	// Update the event
 	return fc.UpdateEvent(ctx, eventID, *event)
 }
 
-// RemoveCoowner removes a coowner from an event
-func (fc *FirestoreConnector) RemoveCoowner(ctx context.Context, eventID string, primaryOwnerID, coownerID int64) error {
-	// First, verify that the user trying to remove a coowner is the primary owner
+// SetCoownerRole changes the role of an existing coowner
+func (fc *FirestoreConnector) SetCoownerRole(ctx context.Context, eventID string, coownerID int64, role model.CoownerRole) error {
 	event, err := fc.ReadEvent(ctx, eventID)
 	if err != nil {
 		return err
 	}
 
-	// Check if the primary owner is actually the owner
-	if event.UserID != primaryOwnerID {
-		return fmt.Errorf("only the primary owner can remove coowners")
+	if !slices.Contains(event.Coowners, coownerID) {
+		return fmt.Errorf("coowner not found")
+	}
+
+	if event.CoownerRoles == nil {
+		event.CoownerRoles = make(map[string]model.CoownerRole)
+	}
+	event.CoownerRoles[strconv.FormatInt(coownerID, 10)] = role
+	return fc.UpdateEvent(ctx, eventID, *event)
+}
+
+// RemoveCoowner removes a coowner and their role from an event
+func (fc *FirestoreConnector) RemoveCoowner(ctx context.Context, eventID string, coownerID int64) error {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return err
 	}
 
 	// Find and remove the coowner
@@ -380,6 +427,10 @@ func (fc *FirestoreConnector) RemoveCoowner(ctx context.Context, eventID string,
 		if existingCoowner == coownerID {
 			// Remove the coowner by slicing
 			event.Coowners = append(event.Coowners[:i], event.Coowners[i+1:]...)
+			delete(event.CoownerRoles, strconv.FormatInt(coownerID, 10))
+			if event.PendingOwner == coownerID {
+				event.PendingOwner = 0
+			}
 
 			// Update the event
 			return fc.UpdateEvent(ctx, eventID, *event)
@@ -398,3 +449,137 @@ func (fc *FirestoreConnector) ListCoowners(ctx context.Context, eventID string)
 
 	return event.Coowners, nil
 }
+
+// IsEventChecker checks if the user may scan tickets, as a designated checker or through their role on the event
+func (fc *FirestoreConnector) IsEventChecker(ctx context.Context, eventID string, userID int64) (bool, error) {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+
+	return event.RoleOf(userID).Can(model.PermissionCheckIn) || slices.Contains(event.Checkers, userID), nil
+}
+
+// AddChecker adds a designated ticket checker to an event
+func (fc *FirestoreConnector) AddChecker(ctx context.Context, eventID string, checkerID int64) error {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(event.Checkers, checkerID) {
+		return fmt.Errorf("user is already a checker")
+	}
+
+	event.Checkers = append(event.Checkers, checkerID)
+	return fc.UpdateEvent(ctx, eventID, *event)
+}
+
+// RemoveChecker removes a designated ticket checker from an event
+func (fc *FirestoreConnector) RemoveChecker(ctx context.Context, eventID string, checkerID int64) error {
+	event, err := fc.ReadEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.Index(event.Checkers, checkerID)
+	if i < 0 {
+		return fmt.Errorf("checker not found")
+	}
+
+	event.Checkers = slices.Delete(event.Checkers, i, i+1)
+	return fc.UpdateEvent(ctx, eventID, *event)
+}
+
+// RemoveParticipantFromEvent unregisters a participant from one event, leaving their other sign-ups intact
+func (fc *FirestoreConnector) RemoveParticipantFromEvent(ctx context.Context, eventID string, participantID string) (*model.Participant, error) {
+	eventRef := fc.client.Collection("events").Doc(eventID)
+	participantRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return err
+		}
+		var event model.Event
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
+		}
+
+		participantDoc, err := tx.Get(participantRef)
+		if err != nil {
+			return err
+		}
+		if err := participantDoc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		i := slices.Index(event.Participants, participantID)
+		j := slices.IndexFunc(participant.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID
+		})
+		if i < 0 && j < 0 {
+			return model.ErrNotSignedUp
+		}
+
+		if i >= 0 {
+			event.Participants = slices.Delete(event.Participants, i, i+1)
+			event.LeaveAgenda(participantID)
+			delete(event.GuestCounts, participantID)
+			if err := tx.Set(eventRef, event); err != nil {
+				return err
+			}
+		}
+		if j >= 0 {
+			participant.SignedUpEvents = slices.Delete(participant.SignedUpEvents, j, j+1)
+			if err := tx.Set(participantRef, participant); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// SetEventStatus moves the event to a new status if its current status allows it, returning the updated event
+func (fc *FirestoreConnector) SetEventStatus(ctx context.Context, eventID string, status model.EventStatus, reason string, at time.Time) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if !event.CurrentStatus().CanBecome(status) {
+			return model.ErrInvalidStatusChange
+		}
+		event.Status = status
+		event.CancelReason = reason
+		event.StatusChangedAt = at.UTC()
+		return nil
+	})
+}
+
+// modifyEvent applies change to the event inside a transaction so concurrent updates cannot interleave
+func (fc *FirestoreConnector) modifyEvent(ctx context.Context, eventID string, change func(event *model.Event) error) (*model.Event, error) {
+	eventRef := fc.client.Collection("events").Doc(eventID)
+
+	var event model.Event
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return fmt.Errorf("error reading event: %w", err)
+		}
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
+		}
+
+		if err := change(&event); err != nil {
+			return err
+		}
+		return tx.Set(eventRef, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.ID = eventID
+	return &event, nil
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SetGuestPolicy sets how many guests each participant may bring and whether guests answer the RSVP questions
+func (fc *FirestoreConnector) SetGuestPolicy(ctx context.Context, eventID string, maxGuests int, guestRSVP bool) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		event.MaxGuests = maxGuests
+		event.GuestRSVP = guestRSVP
+		return nil
+	})
+}
+
+// SetCapacity limits how many people, guests included, can register for the event; 0 removes the limit
+func (fc *FirestoreConnector) SetCapacity(ctx context.Context, eventID string, capacity int) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		event.Capacity = capacity
+		return nil
+	})
+}
+
+// SetGuests replaces the guests a participant registered for an event, keeping the event's headcount within its capacity
+func (fc *FirestoreConnector) SetGuests(ctx context.Context, eventID string, participantID string, guests []model.Guest) (*model.Participant, error) {
+	eventRef := fc.client.Collection("events").Doc(eventID)
+	participantRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		eventDoc, err := tx.Get(eventRef)
+		if err != nil {
+			return err
+		}
+		var event model.Event
+		if err := eventDoc.DataTo(&event); err != nil {
+			return err
+		}
+
+		participantDoc, err := tx.Get(participantRef)
+		if err != nil {
+			return err
+		}
+		if err := participantDoc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(participant.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID
+		})
+		if i < 0 {
+			return model.ErrNotSignedUp
+		}
+
+		// Fewer guests than before is always fine, even if the rules have since been tightened
+		previous := event.GuestCounts[participantID]
+		if len(guests) > previous {
+			if len(guests) > event.MaxGuests {
+				return model.ErrTooManyGuests
+			}
+			confirmed := slices.Contains(event.Participants, participantID)
+			if event.Capacity > 0 && confirmed && event.Headcount()-previous+len(guests) > event.Capacity {
+				return model.ErrEventFull
+			}
+		}
+
+		if event.GuestCounts == nil {
+			event.GuestCounts = make(map[string]int)
+		}
+		if len(guests) == 0 {
+			delete(event.GuestCounts, participantID)
+		} else {
+			event.GuestCounts[participantID] = len(guests)
+		}
+		participant.SignedUpEvents[i].Guests = guests
+
+		if err := tx.Set(eventRef, event); err != nil {
+			return err
+		}
+		return tx.Set(participantRef, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// SetGuestCheckIn checks the given guests of a participant in, or undoes it, returning how many changed
+func (fc *FirestoreConnector) SetGuestCheckIn(ctx context.Context, participantID string, eventID string, guestIndexes []int, checkedIn bool, at time.Time, by int64) (*model.Participant, int, error) {
+	docRef := fc.client.Collection("participants").Doc(participantID)
+
+	var participant model.Participant
+	var changed int
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		changed = 0
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&participant); err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(participant.SignedUpEvents, func(s model.SignedUpEvent) bool {
+			return s.EventID == eventID
+		})
+		if i < 0 {
+			return model.ErrNotSignedUp
+		}
+		signedUpEvent := &participant.SignedUpEvents[i]
+		if signedUpEvent.PendingApproval {
+			return model.ErrApplicationPending
+		}
+
+		for _, index := range guestIndexes {
+			if index < 0 || index >= len(signedUpEvent.Guests) {
+				continue
+			}
+			guest := &signedUpEvent.Guests[index]
+			if guest.CheckedIn == checkedIn {
+				continue
+			}
+			guest.CheckedIn = checkedIn
+			guest.CheckedInAt = time.Time{}
+			guest.CheckedInBy = 0
+			if checkedIn {
+				guest.CheckedInAt = at.UTC()
+				guest.CheckedInBy = by
+			}
+			changed++
+		}
+		if changed == 0 {
+			return nil
+		}
+		return tx.Set(docRef, participant)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &participant, changed, nil
+}
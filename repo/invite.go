@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateInvite stores a new invite under a random token and returns the token
+func (fc *FirestoreConnector) CreateInvite(ctx context.Context, invite model.Invite) (string, error) {
+	docRef := fc.client.Collection("invites").NewDoc()
+	invite.Token = docRef.ID
+	_, err := docRef.Set(ctx, invite)
+	if err != nil {
+		return "", err
+	}
+	return docRef.ID, nil
+}
+
+// ReadInvite reads an invite by its token
+func (fc *FirestoreConnector) ReadInvite(ctx context.Context, token string) (*model.Invite, error) {
+	doc, err := fc.client.Collection("invites").Doc(token).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, model.ErrInviteDoesNotExist
+		}
+		return nil, err
+	}
+
+	var invite model.Invite
+	err = doc.DataTo(&invite)
+	if err != nil {
+		return nil, fmt.Errorf("error converting document data to invite: %w", err)
+	}
+	return &invite, nil
+}
+
+// UpdateInvite overwrites an existing invite
+func (fc *FirestoreConnector) UpdateInvite(ctx context.Context, invite model.Invite) error {
+	_, err := fc.client.Collection("invites").Doc(invite.Token).Set(ctx, invite)
+	return err
+}
+
+// ListInvitesByEventID lists all invites of an event, oldest first
+func (fc *FirestoreConnector) ListInvitesByEventID(ctx context.Context, eventID string) ([]model.Invite, error) {
+	iter := fc.client.Collection("invites").Where("eventID", "==", eventID).Documents(ctx)
+	defer iter.Stop()
+
+	var invites []model.Invite
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var invite model.Invite
+		if err := doc.DataTo(&invite); err != nil {
+			return nil, fmt.Errorf("error converting document data to invite: %w", err)
+		}
+		invites = append(invites, invite)
+	}
+
+	sort.Slice(invites, func(i, j int) bool {
+		return invites[i].CreatedAt.Before(invites[j].CreatedAt)
+	})
+	return invites, nil
+}
+
+// RedeemInvite atomically uses up one use of an invite, rejecting revoked, expired and used-up invites
+func (fc *FirestoreConnector) RedeemInvite(ctx context.Context, token string, now time.Time) (*model.Invite, error) {
+	docRef := fc.client.Collection("invites").Doc(token)
+
+	var invite model.Invite
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return model.ErrInviteDoesNotExist
+		} else if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&invite); err != nil {
+			return err
+		}
+
+		switch {
+		case invite.Revoked:
+			return model.ErrInviteRevoked
+		case !invite.ExpiresAt.IsZero() && !now.Before(invite.ExpiresAt):
+			return model.ErrInviteExpired
+		case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+			return model.ErrInviteUsedUp
+		}
+
+		invite.Uses++
+		return tx.Set(docRef, invite)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// ReleaseInvite atomically gives back a use of an invite, for when the sign-up it was redeemed for failed
+func (fc *FirestoreConnector) ReleaseInvite(ctx context.Context, token string) error {
+	docRef := fc.client.Collection("invites").Doc(token)
+
+	return fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return model.ErrInviteDoesNotExist
+		} else if err != nil {
+			return err
+		}
+		var invite model.Invite
+		if err := doc.DataTo(&invite); err != nil {
+			return err
+		}
+
+		if invite.Uses == 0 {
+			return nil
+		}
+		invite.Uses--
+		return tx.Set(docRef, invite)
+	})
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"slices"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReadOrganiser reads an organiser's preferences, returning nil if none are stored yet
+func (fc *FirestoreConnector) ReadOrganiser(ctx context.Context, userID int64) (*model.Organiser, error) {
+	doc, err := fc.client.Collection("organisers").Doc(strconv.FormatInt(userID, 10)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var organiser model.Organiser
+	err = doc.DataTo(&organiser)
+	if err != nil {
+		return nil, err
+	}
+	return &organiser, nil
+}
+
+// UpdateOrganiser creates or overwrites an organiser's preferences
+func (fc *FirestoreConnector) UpdateOrganiser(ctx context.Context, organiser model.Organiser) error {
+	_, err := fc.client.Collection("organisers").Doc(strconv.FormatInt(organiser.UserID, 10)).Set(ctx, organiser)
+	return err
+}
+
+// BanUser adds a user to an organiser's ban list
+func (fc *FirestoreConnector) BanUser(ctx context.Context, organiserID int64, banned model.BannedUser) error {
+	docRef := fc.client.Collection("organisers").Doc(strconv.FormatInt(organiserID, 10))
+
+	return fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		organiser := model.Organiser{UserID: organiserID}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&organiser); err != nil {
+				return err
+			}
+		}
+
+		if slices.ContainsFunc(organiser.BannedUsers, func(b model.BannedUser) bool { return b.UserID == banned.UserID }) {
+			return model.ErrAlreadyBanned
+		}
+		organiser.BannedUsers = append(organiser.BannedUsers, banned)
+		return tx.Set(docRef, organiser)
+	})
+}
+
+// UnbanUser removes a user from an organiser's ban list
+func (fc *FirestoreConnector) UnbanUser(ctx context.Context, organiserID int64, userID int64) error {
+	docRef := fc.client.Collection("organisers").Doc(strconv.FormatInt(organiserID, 10))
+
+	return fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return model.ErrNotBanned
+		} else if err != nil {
+			return err
+		}
+
+		var organiser model.Organiser
+		if err := doc.DataTo(&organiser); err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(organiser.BannedUsers, func(b model.BannedUser) bool { return b.UserID == userID })
+		if i < 0 {
+			return model.ErrNotBanned
+		}
+		organiser.BannedUsers = slices.Delete(organiser.BannedUsers, i, i+1)
+		return tx.Set(docRef, organiser)
+	})
+}
+
+// IsUserBanned reports whether the organiser has banned the user
+func (fc *FirestoreConnector) IsUserBanned(ctx context.Context, organiserID int64, userID int64) (bool, error) {
+	organiser, err := fc.ReadOrganiser(ctx, organiserID)
+	if err != nil {
+		return false, err
+	}
+	return organiser != nil && slices.ContainsFunc(organiser.BannedUsers, func(b model.BannedUser) bool { return b.UserID == userID }), nil
+}
+
+// UpdateOrganiserProfile records an organiser's Telegram name and username, keeping their other preferences
+func (fc *FirestoreConnector) UpdateOrganiserProfile(ctx context.Context, userID int64, name string, username string) error {
+	_, err := fc.client.Collection("organisers").Doc(strconv.FormatInt(userID, 10)).Set(ctx, map[string]interface{}{
+		"userid":   userID,
+		"name":     name,
+		"username": username,
+	}, firestore.MergeAll)
+	return err
+}
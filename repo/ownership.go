@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"slices"
+	"strconv"
+)
+
+// NominateOwner records which coowner the primary owner wants to hand the event over to; nomineeID 0 withdraws the nomination
+func (fc *FirestoreConnector) NominateOwner(ctx context.Context, eventID string, ownerID int64, nomineeID int64) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if event.UserID != ownerID {
+			return model.ErrNotPrimaryOwner
+		}
+		if nomineeID != 0 && !slices.Contains(event.Coowners, nomineeID) {
+			return model.ErrNotCoowner
+		}
+		event.PendingOwner = nomineeID
+		return nil
+	})
+}
+
+// AcceptOwnership makes the nominated coowner the primary owner and keeps the previous owner on the team as an admin
+func (fc *FirestoreConnector) AcceptOwnership(ctx context.Context, eventID string, nomineeID int64) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if event.PendingOwner == 0 || event.PendingOwner != nomineeID {
+			return model.ErrNoTransferPending
+		}
+		i := slices.Index(event.Coowners, nomineeID)
+		if i < 0 {
+			return model.ErrNotCoowner
+		}
+
+		previousOwnerID := event.UserID
+		event.Coowners[i] = previousOwnerID
+		if event.CoownerRoles == nil {
+			event.CoownerRoles = make(map[string]model.CoownerRole)
+		}
+		delete(event.CoownerRoles, strconv.FormatInt(nomineeID, 10))
+		event.CoownerRoles[strconv.FormatInt(previousOwnerID, 10)] = model.RoleAdmin
+		event.UserID = nomineeID
+		event.PendingOwner = 0
+		return nil
+	})
+}
+
+// DeclineOwnership clears a nomination addressed to the user
+func (fc *FirestoreConnector) DeclineOwnership(ctx context.Context, eventID string, nomineeID int64) (*model.Event, error) {
+	return fc.modifyEvent(ctx, eventID, func(event *model.Event) error {
+		if event.PendingOwner == 0 || event.PendingOwner != nomineeID {
+			return model.ErrNoTransferPending
+		}
+		event.PendingOwner = 0
+		return nil
+	})
+}
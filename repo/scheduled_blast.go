@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateScheduledBlast stores a new scheduled blast and returns its ID
+func (fc *FirestoreConnector) CreateScheduledBlast(ctx context.Context, blast model.ScheduledBlast) (string, error) {
+	docRef := fc.client.Collection("scheduledBlasts").NewDoc()
+	blast.ID = docRef.ID
+	_, err := docRef.Set(ctx, blast)
+	if err != nil {
+		return "", err
+	}
+	return docRef.ID, nil
+}
+
+// ReadScheduledBlast reads a scheduled blast by its ID
+func (fc *FirestoreConnector) ReadScheduledBlast(ctx context.Context, blastID string) (*model.ScheduledBlast, error) {
+	doc, err := fc.client.Collection("scheduledBlasts").Doc(blastID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, model.ErrBlastDoesNotExist
+		}
+		return nil, err
+	}
+
+	var blast model.ScheduledBlast
+	err = doc.DataTo(&blast)
+	if err != nil {
+		return nil, fmt.Errorf("error converting document data to scheduled blast: %w", err)
+	}
+	return &blast, nil
+}
+
+// UpdateScheduledBlast overwrites an existing scheduled blast
+func (fc *FirestoreConnector) UpdateScheduledBlast(ctx context.Context, blast model.ScheduledBlast) error {
+	_, err := fc.client.Collection("scheduledBlasts").Doc(blast.ID).Set(ctx, blast)
+	return err
+}
+
+// ListScheduledBlastsByEventID lists the pending blasts of an event, earliest first
+func (fc *FirestoreConnector) ListScheduledBlastsByEventID(ctx context.Context, eventID string) ([]model.ScheduledBlast, error) {
+	return fc.listScheduledBlastsByStatus(ctx, eventID, model.BlastStatusPending)
+}
+
+// ListFailedScheduledBlastsByEventID lists the blasts of an event that could not be sent, earliest first
+func (fc *FirestoreConnector) ListFailedScheduledBlastsByEventID(ctx context.Context, eventID string) ([]model.ScheduledBlast, error) {
+	return fc.listScheduledBlastsByStatus(ctx, eventID, model.BlastStatusFailed)
+}
+
+func (fc *FirestoreConnector) listScheduledBlastsByStatus(ctx context.Context, eventID string, blastStatus model.BlastStatus) ([]model.ScheduledBlast, error) {
+	iter := fc.client.Collection("scheduledBlasts").Where("eventID", "==", eventID).Documents(ctx)
+
+	blasts, err := collectScheduledBlasts(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := blasts[:0]
+	for _, blast := range blasts {
+		if blast.Status == blastStatus {
+			matching = append(matching, blast)
+		}
+	}
+	return matching, nil
+}
+
+// ListDueScheduledBlasts lists pending blasts whose send time is at or before now
+func (fc *FirestoreConnector) ListDueScheduledBlasts(ctx context.Context, now time.Time) ([]model.ScheduledBlast, error) {
+	// Filter on status only so no composite index is needed; the pending set is small
+	iter := fc.client.Collection("scheduledBlasts").Where("status", "==", string(model.BlastStatusPending)).Documents(ctx)
+
+	blasts, err := collectScheduledBlasts(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	due := blasts[:0]
+	for _, blast := range blasts {
+		if !blast.SendAt.After(now) {
+			due = append(due, blast)
+		}
+	}
+	return due, nil
+}
+
+// ClaimScheduledBlast atomically moves a pending blast to the sending state so it is only sent once
+func (fc *FirestoreConnector) ClaimScheduledBlast(ctx context.Context, blastID string) (*model.ScheduledBlast, error) {
+	docRef := fc.client.Collection("scheduledBlasts").Doc(blastID)
+
+	var blast model.ScheduledBlast
+	err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&blast); err != nil {
+			return err
+		}
+		if blast.Status != model.BlastStatusPending {
+			return model.ErrBlastNotPending
+		}
+		blast.Status = model.BlastStatusSending
+		return tx.Set(docRef, blast)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blast, nil
+}
+
+// FailInterruptedScheduledBlasts marks blasts left in the sending state, by a restart part-way through sending, as failed.
+// They may have reached some participants, so they are not retried automatically. Returns the blasts it marked.
+func (fc *FirestoreConnector) FailInterruptedScheduledBlasts(ctx context.Context) ([]model.ScheduledBlast, error) {
+	iter := fc.client.Collection("scheduledBlasts").Where("status", "==", string(model.BlastStatusSending)).Documents(ctx)
+	blasts, err := collectScheduledBlasts(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []model.ScheduledBlast
+	for _, stale := range blasts {
+		docRef := fc.client.Collection("scheduledBlasts").Doc(stale.ID)
+		var blast model.ScheduledBlast
+		stillSending := false
+		err := fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			doc, err := tx.Get(docRef)
+			if err != nil {
+				return err
+			}
+			if err := doc.DataTo(&blast); err != nil {
+				return err
+			}
+			stillSending = blast.Status == model.BlastStatusSending
+			if !stillSending {
+				return nil
+			}
+			blast.Status = model.BlastStatusFailed
+			blast.FailedAt = time.Now().UTC()
+			return tx.Set(docRef, blast)
+		})
+		if err != nil {
+			log.Printf("error failing interrupted scheduled blast %s: %v", stale.ID, err)
+			continue
+		}
+		if !stillSending {
+			continue
+		}
+		blast.ID = stale.ID
+		failed = append(failed, blast)
+	}
+	return failed, nil
+}
+
+// UpdatePendingScheduledBlast overwrites a blast only if it has not started sending yet
+func (fc *FirestoreConnector) UpdatePendingScheduledBlast(ctx context.Context, blast model.ScheduledBlast) error {
+	docRef := fc.client.Collection("scheduledBlasts").Doc(blast.ID)
+
+	return fc.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return model.ErrBlastDoesNotExist
+			}
+			return err
+		}
+		var current model.ScheduledBlast
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+		if current.Status != model.BlastStatusPending {
+			return model.ErrBlastNotPending
+		}
+		return tx.Set(docRef, blast)
+	})
+}
+
+func collectScheduledBlasts(iter *firestore.DocumentIterator) ([]model.ScheduledBlast, error) {
+	var blasts []model.ScheduledBlast
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var blast model.ScheduledBlast
+		err = doc.DataTo(&blast)
+		if err != nil {
+			log.Printf("error converting document data to scheduled blast: %v", err)
+			continue
+		}
+		blast.ID = doc.Ref.ID
+		blasts = append(blasts, blast)
+	}
+
+	sort.Slice(blasts, func(i, j int) bool {
+		return blasts[i].SendAt.Before(blasts[j].SendAt)
+	})
+	return blasts, nil
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/api/iterator"
+)
+
+// CreateSeries stores a new event series and returns its ID
+func (fc *FirestoreConnector) CreateSeries(ctx context.Context, series model.EventSeries) (string, error) {
+	docRef := fc.client.Collection("eventSeries").NewDoc()
+	series.ID = docRef.ID
+	_, err := docRef.Set(ctx, series)
+	if err != nil {
+		return "", err
+	}
+	return docRef.ID, nil
+}
+
+// ReadSeries reads an event series by its ID
+func (fc *FirestoreConnector) ReadSeries(ctx context.Context, seriesID string) (*model.EventSeries, error) {
+	doc, err := fc.client.Collection("eventSeries").Doc(seriesID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var series model.EventSeries
+	err = doc.DataTo(&series)
+	if err != nil {
+		return nil, fmt.Errorf("error converting document data to series: %w", err)
+	}
+	return &series, nil
+}
+
+// UpdateSeries overwrites an existing event series
+func (fc *FirestoreConnector) UpdateSeries(ctx context.Context, series model.EventSeries) error {
+	_, err := fc.client.Collection("eventSeries").Doc(series.ID).Set(ctx, series)
+	return err
+}
+
+// ListSeriesEvents returns the occurrences of a series in order
+func (fc *FirestoreConnector) ListSeriesEvents(ctx context.Context, seriesID string) ([]model.Event, error) {
+	iter := fc.client.Collection("events").Where("seriesID", "==", seriesID).Documents(ctx)
+	defer iter.Stop()
+
+	var events []model.Event
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var event model.Event
+		if err := doc.DataTo(&event); err != nil {
+			return nil, fmt.Errorf("error converting document data to event: %w", err)
+		}
+		event.ID = doc.Ref.ID
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].SeriesIndex < events[j].SeriesIndex
+	})
+	return events, nil
+}
+
+// UpdateLaterOccurrences applies change to every occurrence after afterIndex that can still be edited, returning the updated occurrences
+func (fc *FirestoreConnector) UpdateLaterOccurrences(ctx context.Context, seriesID string, afterIndex int, change func(event *model.Event)) ([]model.Event, error) {
+	events, err := fc.ListSeriesEvents(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []model.Event
+	for _, event := range events {
+		if event.SeriesIndex <= afterIndex || event.IsDeleted() {
+			continue
+		}
+		switch event.CurrentStatus() {
+		case model.EventStatusCancelled, model.EventStatusCompleted:
+			continue
+		}
+
+		occurrence, err := fc.modifyEvent(ctx, event.ID, func(event *model.Event) error {
+			change(event)
+			return nil
+		})
+		if err != nil {
+			return updated, fmt.Errorf("error updating occurrence %s: %w", event.ID, err)
+		}
+		occurrence.ID = event.ID
+		updated = append(updated, *occurrence)
+	}
+	return updated, nil
+}
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"EventBot/model"
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/api/iterator"
+)
+
+// CreateTemplate stores a new event template and returns its ID
+func (fc *FirestoreConnector) CreateTemplate(ctx context.Context, template model.EventTemplate) (string, error) {
+	docRef := fc.client.Collection("eventTemplates").NewDoc()
+	template.ID = docRef.ID
+	_, err := docRef.Set(ctx, template)
+	if err != nil {
+		return "", err
+	}
+	return docRef.ID, nil
+}
+
+// ListTemplatesByOwnerID returns an organiser's templates sorted by name
+func (fc *FirestoreConnector) ListTemplatesByOwnerID(ctx context.Context, ownerID int64) ([]model.EventTemplate, error) {
+	iter := fc.client.Collection("eventTemplates").Where("ownerID", "==", ownerID).Documents(ctx)
+	defer iter.Stop()
+
+	var templates []model.EventTemplate
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var template model.EventTemplate
+		if err := doc.DataTo(&template); err != nil {
+			return nil, fmt.Errorf("error converting document data to template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// DeleteTemplate removes an event template
+func (fc *FirestoreConnector) DeleteTemplate(ctx context.Context, templateID string) error {
+	_, err := fc.client.Collection("eventTemplates").Doc(templateID).Delete(ctx)
+	return err
+}